@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+// Package analyzer compares the paths documented in API specs with the paths
+// observed in traffic, both held in a pathtrie.PathTrie.
+package analyzer
+
+import (
+	"github.com/5gsec/api-speculator/internal/apispec"
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+// valuePaths returns the sorted full paths of every value-holding node of pt.
+func valuePaths(pt *pathtrie.PathTrie) []string {
+	return pt.PathsWithValue(func(any) bool {
+		return true
+	})
+}
+
+// matchSpecPath returns the spec full path matching the observed path, after
+// replacing its dynamic segments with params, or as is if that doesn't match.
+// The parameterized observed path is returned too.
+func matchSpecPath(spec *pathtrie.PathTrie, observedPath string) (specPath, normalizedPath string, ok bool) {
+	normalizedPath = apispec.UnifyParameterizedPathIfApplicable(observedPath, false)
+	if specPath, _, ok = spec.GetPathAndValue(normalizedPath); ok {
+		return specPath, normalizedPath, true
+	}
+	if specPath, _, ok = spec.GetPathAndValue(observedPath); ok {
+		return specPath, normalizedPath, true
+	}
+
+	return "", normalizedPath, false
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+// CoverageStats counts the spec paths matched by at least one observed path.
+type CoverageStats struct {
+	Covered int
+	Total   int
+
+	// Ratio is Covered over Total, or 0 if there is no spec path.
+	Ratio float64
+}
+
+// CoverageReport is the coverage of all the spec paths, and of the spec paths
+// of each top-level prefix.
+type CoverageReport struct {
+	CoverageStats
+
+	// ByPrefix is the coverage of the spec paths by top-level prefix, e.g.
+	// /users for /users/{id}.
+	ByPrefix map[string]CoverageStats
+}
+
+// Coverage computes how many of the value-holding spec paths are matched by at
+// least one observed path, matching them as Diff does.
+func Coverage(spec, observed *pathtrie.PathTrie) CoverageReport {
+	diff := Diff(spec, observed)
+
+	report := CoverageReport{
+		ByPrefix: make(map[string]CoverageStats),
+	}
+	count := func(specPath string, covered bool) {
+		prefix := topLevelPrefix(specPath, spec.PathSeparator)
+		prefixCoverage := report.ByPrefix[prefix]
+		prefixCoverage.add(covered)
+		report.ByPrefix[prefix] = prefixCoverage
+		report.add(covered)
+	}
+	for _, specPath := range diff.InBoth {
+		count(specPath, true)
+	}
+	for _, specPath := range diff.OnlyInSpec {
+		count(specPath, false)
+	}
+
+	return report
+}
+
+func (c *CoverageStats) add(covered bool) {
+	c.Total++
+	if covered {
+		c.Covered++
+	}
+	c.Ratio = float64(c.Covered) / float64(c.Total)
+}
+
+// topLevelPrefix returns the first segment of path, along with the leading
+// separator of absolute paths.
+func topLevelPrefix(path, separator string) string {
+	isAbsolute := strings.HasPrefix(path, separator)
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, separator), separator)
+	if isAbsolute {
+		return separator + segment
+	}
+
+	return segment
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+func TestCoverage(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *pathtrie.PathTrie
+		observed *pathtrie.PathTrie
+		expected CoverageReport
+	}{
+		{
+			name:     "per prefix coverage",
+			spec:     newTrie("/users", "/users/{id}", "/users/{id}/posts", "/orders", "/orders/{id}", "/health"),
+			observed: newTrie("/users/42", "/users/43/posts", "/orders/7", "/unknown"),
+			expected: CoverageReport{
+				CoverageStats: CoverageStats{Covered: 3, Total: 6, Ratio: 0.5},
+				ByPrefix: map[string]CoverageStats{
+					"/users":  {Covered: 2, Total: 3, Ratio: 2.0 / 3},
+					"/orders": {Covered: 1, Total: 2, Ratio: 0.5},
+					"/health": {Covered: 0, Total: 1, Ratio: 0},
+				},
+			},
+		},
+		{
+			name:     "empty spec",
+			spec:     newTrie(),
+			observed: newTrie("/users/42"),
+			expected: CoverageReport{
+				ByPrefix: map[string]CoverageStats{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Coverage(tt.spec, tt.observed)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestTopLevelPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "absolute path",
+			path:     "/users/{id}",
+			expected: "/users",
+		},
+		{
+			name:     "single segment",
+			path:     "/users",
+			expected: "/users",
+		},
+		{
+			name:     "relative path",
+			path:     "users/{id}",
+			expected: "users",
+		},
+		{
+			name:     "root",
+			path:     "/",
+			expected: "/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, topLevelPrefix(tt.path, "/"))
+		})
+	}
+}
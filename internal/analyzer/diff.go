@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"slices"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+// DiffResult lists the paths found in a spec trie, an observed trie or both.
+type DiffResult struct {
+	// OnlyInSpec are the spec paths that no observed path matched, i.e.
+	// documented endpoints that are never used.
+	OnlyInSpec []string
+
+	// OnlyInObserved are the observed paths, with their dynamic segments
+	// replaced by params, that match no spec path, i.e. undocumented endpoints.
+	OnlyInObserved []string
+
+	// InBoth are the spec paths matched by at least one observed path.
+	InBoth []string
+}
+
+// Diff compares the value-holding paths of spec and observed. Observed paths are
+// parameterized with apispec.UnifyParameterizedPathIfApplicable and matched
+// against spec with the path params aware lookups of PathTrie, so /v1/users/42
+// covers /v1/users/{id}. All the slices are sorted.
+func Diff(spec, observed *pathtrie.PathTrie) DiffResult {
+	matched := make(map[string]struct{})
+	unmatched := make(map[string]struct{})
+	for _, observedPath := range valuePaths(observed) {
+		specPath, normalizedPath, ok := matchSpecPath(spec, observedPath)
+		if ok {
+			matched[specPath] = struct{}{}
+		} else {
+			unmatched[normalizedPath] = struct{}{}
+		}
+	}
+
+	result := DiffResult{
+		OnlyInSpec:     []string{},
+		OnlyInObserved: []string{},
+		InBoth:         []string{},
+	}
+	for _, specPath := range valuePaths(spec) {
+		if _, ok := matched[specPath]; ok {
+			result.InBoth = append(result.InBoth, specPath)
+		} else {
+			result.OnlyInSpec = append(result.OnlyInSpec, specPath)
+		}
+	}
+	for observedPath := range unmatched {
+		result.OnlyInObserved = append(result.OnlyInObserved, observedPath)
+	}
+	slices.Sort(result.OnlyInObserved)
+
+	return result
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+func newTrie(paths ...string) *pathtrie.PathTrie {
+	trie := pathtrie.New()
+	for _, path := range paths {
+		trie.Insert(path, struct{}{})
+	}
+	return &trie
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *pathtrie.PathTrie
+		observed *pathtrie.PathTrie
+		expected DiffResult
+	}{
+		{
+			name:     "concrete ids cover templated paths",
+			spec:     newTrie("/v1/users", "/v1/users/{id}", "/v1/users/{id}/posts", "/v1/legacy"),
+			observed: newTrie("/v1/users/42", "/v1/users/43/posts", "/v1/users"),
+			expected: DiffResult{
+				OnlyInSpec:     []string{"/v1/legacy"},
+				OnlyInObserved: []string{},
+				InBoth:         []string{"/v1/users", "/v1/users/{id}", "/v1/users/{id}/posts"},
+			},
+		},
+		{
+			name:     "undocumented paths are parameterized",
+			spec:     newTrie("/v1/users/{id}"),
+			observed: newTrie("/v1/orders/1", "/v1/orders/2", "/v1/users/1/sessions", "/v1/users/1"),
+			expected: DiffResult{
+				OnlyInSpec:     []string{},
+				OnlyInObserved: []string{"/v1/orders/{param1}", "/v1/users/{param1}/sessions"},
+				InBoth:         []string{"/v1/users/{id}"},
+			},
+		},
+		{
+			name:     "static spec segments looking like ids",
+			spec:     newTrie("/v1/reports/2024"),
+			observed: newTrie("/v1/reports/2024"),
+			expected: DiffResult{
+				OnlyInSpec:     []string{},
+				OnlyInObserved: []string{},
+				InBoth:         []string{"/v1/reports/2024"},
+			},
+		},
+		{
+			name:     "empty observed",
+			spec:     newTrie("/v1/users"),
+			observed: newTrie(),
+			expected: DiffResult{
+				OnlyInSpec:     []string{"/v1/users"},
+				OnlyInObserved: []string{},
+				InBoth:         []string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Diff(tt.spec, tt.observed)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+	"github.com/5gsec/api-speculator/internal/util"
+)
+
+// Conflict is a path defined with different values by two of the specs given
+// to MergeSpecs.
+type Conflict struct {
+	Path string
+
+	// Kept is the index of the spec whose value was kept, i.e. the first one
+	// defining Path, and KeptValue its value.
+	Kept      int
+	KeptValue any
+
+	// Overridden is the index of the spec whose value was discarded, and
+	// OverriddenValue its value.
+	Overridden      int
+	OverriddenValue any
+}
+
+// MergeSpecs inserts the value-holding paths of all tries, expected to use the
+// same path separator, into a new trie. When several tries define the same path,
+// the value of the first one is kept and a Conflict is reported for every other
+// one holding a different value. Conflicts are ordered by overriding spec, then
+// path.
+func MergeSpecs(tries ...*pathtrie.PathTrie) (*pathtrie.PathTrie, []Conflict) {
+	merged := pathtrie.New()
+	if len(tries) > 0 {
+		merged = pathtrie.NewWithPathSeparator(tries[0].PathSeparator)
+	}
+
+	owners := make(map[string]int)
+	var conflicts []Conflict
+	for idx, trie := range tries {
+		for _, pathValue := range sortedPathValues(trie) {
+			isNewPath := merged.InsertMerge(pathValue.FullPath, pathValue.Value, func(existing, newV *any) {
+				if util.IsNil(*existing) {
+					*existing = *newV
+					return
+				}
+				if !reflect.DeepEqual(*existing, *newV) {
+					conflicts = append(conflicts, Conflict{
+						Path:            pathValue.FullPath,
+						Kept:            owners[pathValue.FullPath],
+						KeptValue:       *existing,
+						Overridden:      idx,
+						OverriddenValue: *newV,
+					})
+				}
+			})
+			if isNewPath {
+				owners[pathValue.FullPath] = idx
+			}
+		}
+	}
+
+	return &merged, conflicts
+}
+
+// sortedPathValues returns the full path and value of every value-holding node
+// of pt, including the root path and the paths ending with a separator, sorted
+// by full path.
+func sortedPathValues(pt *pathtrie.PathTrie) []pathtrie.PathValue {
+	var pathValues []pathtrie.PathValue
+	for fullPath, value := range pt.All() {
+		pathValues = append(pathValues, pathtrie.PathValue{FullPath: fullPath, Value: value})
+	}
+	slices.SortFunc(pathValues, func(a, b pathtrie.PathValue) int {
+		return strings.Compare(a.FullPath, b.FullPath)
+	})
+
+	return pathValues
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+func newValueTrie(pathsAndValues map[string]any) *pathtrie.PathTrie {
+	trie := pathtrie.New()
+	for path, value := range pathsAndValues {
+		trie.Insert(path, value)
+	}
+	return &trie
+}
+
+func TestMergeSpecs(t *testing.T) {
+	users := newValueTrie(map[string]any{
+		"/v1/users":      "users",
+		"/v1/users/{id}": "users",
+		"/health":        "users",
+	})
+	orders := newValueTrie(map[string]any{
+		"/v1/orders": "orders",
+		"/health":    "orders",
+	})
+	billing := newValueTrie(map[string]any{
+		"/v1/users/{id}": "billing",
+		"/health":        "users",
+	})
+
+	merged, conflicts := MergeSpecs(users, orders, billing)
+
+	assert.Equal(t, []string{"/health", "/v1/orders", "/v1/users", "/v1/users/{id}"}, valuePaths(merged))
+	for path, want := range map[string]any{
+		"/health":        "users",
+		"/v1/orders":     "orders",
+		"/v1/users/{id}": "users",
+	} {
+		got, ok := merged.GetValueOK(path)
+		assert.True(t, ok, path)
+		assert.Equal(t, want, got, path)
+	}
+	assert.Equal(t, []Conflict{
+		{Path: "/health", Kept: 0, KeptValue: "users", Overridden: 1, OverriddenValue: "orders"},
+		{Path: "/v1/users/{id}", Kept: 0, KeptValue: "users", Overridden: 2, OverriddenValue: "billing"},
+	}, conflicts)
+}
+
+func TestMergeSpecs_markerPaths(t *testing.T) {
+	spec := newValueTrie(map[string]any{
+		"/":        "root",
+		"/v1/foo/": "foo",
+		"/v1/bar":  "bar",
+	})
+
+	merged, conflicts := MergeSpecs(spec)
+
+	assert.Equal(t, valuePaths(spec), valuePaths(merged))
+	assert.Equal(t, []string{"/", "/v1/bar", "/v1/foo/"}, valuePaths(merged))
+	assert.Equal(t, "root", merged.GetValue("/"))
+	assert.Equal(t, "foo", merged.GetValue("/v1/foo/"))
+	assert.Nil(t, conflicts)
+}
+
+func TestMergeSpecs_noTries(t *testing.T) {
+	merged, conflicts := MergeSpecs()
+	assert.Equal(t, 0, merged.Size())
+	assert.Nil(t, conflicts)
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+const (
+	// LowConfidenceThreshold is the confidence below which an orphan is flagged
+	// as low-confidence.
+	LowConfidenceThreshold = 0.5
+
+	// normalizationPenalty scales the confidence of orphans whose path was
+	// parameterized, since the dynamic segments detection may be wrong.
+	normalizationPenalty = 0.8
+)
+
+// OrphanResult is an observed path matching no spec path.
+type OrphanResult struct {
+	ObservedPath string
+
+	// NormalizedPath is ObservedPath with its dynamic segments replaced by
+	// params.
+	NormalizedPath string
+
+	// NearestSpecPath is the longest spec path matching a prefix of
+	// ObservedPath, empty if there is none.
+	NearestSpecPath string
+
+	// Confidence that the path is truly undocumented, from 0 to 1. It is 1 when
+	// no spec path matches a prefix of ObservedPath, decreases with the share
+	// of segments matched by NearestSpecPath, weighted by its MatchSpecificity
+	// as segments matched by path params are weaker evidence, and is lowered
+	// when ObservedPath needed to be parameterized.
+	Confidence float64
+
+	// LowConfidence is set when Confidence is below LowConfidenceThreshold,
+	// e.g. for deep partial matches that may stem from a flawed normalization.
+	LowConfidence bool
+}
+
+// DetectOrphans returns the observed paths matching no spec path, sorted by
+// observed path, with a confidence score.
+func DetectOrphans(spec, observed *pathtrie.PathTrie) []OrphanResult {
+	var results []OrphanResult
+	for _, observedPath := range valuePaths(observed) {
+		_, normalizedPath, ok := matchSpecPath(spec, observedPath)
+		if ok {
+			continue
+		}
+
+		result := OrphanResult{
+			ObservedPath:   observedPath,
+			NormalizedPath: normalizedPath,
+			Confidence:     1,
+		}
+		if node, remaining, ok := spec.MatchPrefix(observedPath); ok {
+			total := segmentCount(observedPath, spec.PathSeparator)
+			matched := total - len(remaining)
+			result.NearestSpecPath = node.FullPath
+			result.Confidence = 1 - float64(matched)/float64(total)*node.MatchSpecificity()
+		}
+		if normalizedPath != observedPath {
+			result.Confidence *= normalizationPenalty
+		}
+		result.LowConfidence = result.Confidence < LowConfidenceThreshold
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// segmentCount returns the number of segments of path, ignoring the leading
+// separator of absolute paths.
+func segmentCount(path, separator string) int {
+	return len(strings.Split(strings.TrimPrefix(path, separator), separator))
+}
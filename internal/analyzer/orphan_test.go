@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectOrphans(t *testing.T) {
+	spec := newTrie("/v1/users/{id}", "/v1/orders")
+	observed := newTrie(
+		"/v1/users/42",
+		"/v1/users/42/sessions/abc",
+		"/v1/orders/list",
+		"/v2/orders/7",
+		"/metrics",
+	)
+
+	result := DetectOrphans(spec, observed)
+
+	assert.Len(t, result, 4)
+	expected := []OrphanResult{
+		{
+			ObservedPath:   "/metrics",
+			NormalizedPath: "/metrics",
+			Confidence:     1,
+		},
+		{
+			ObservedPath:    "/v1/orders/list",
+			NormalizedPath:  "/v1/orders/list",
+			NearestSpecPath: "/v1/orders",
+			Confidence:      0.33,
+			LowConfidence:   true,
+		},
+		{
+			ObservedPath:    "/v1/users/42/sessions/abc",
+			NormalizedPath:  "/v1/users/{param1}/sessions/abc",
+			NearestSpecPath: "/v1/users/{id}",
+			Confidence:      0.48,
+			LowConfidence:   true,
+		},
+		{
+			ObservedPath:   "/v2/orders/7",
+			NormalizedPath: "/v2/orders/{param1}",
+			Confidence:     0.8,
+		},
+	}
+	for idx, want := range expected {
+		got := result[idx]
+		assert.Equal(t, want.ObservedPath, got.ObservedPath)
+		assert.Equal(t, want.NormalizedPath, got.NormalizedPath)
+		assert.Equal(t, want.NearestSpecPath, got.NearestSpecPath)
+		assert.InDelta(t, want.Confidence, got.Confidence, 0.01, want.ObservedPath)
+		assert.Equal(t, want.LowConfidence, got.LowConfidence, want.ObservedPath)
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+// shadowSegment fills the param segments of the requests built by
+// DetectShadowedPaths when both paths have a param.
+const shadowSegment = "shadowed"
+
+// ShadowPair is a pair of paths of a trie that both match Request.
+type ShadowPair struct {
+	// First and Second are the paths of the pair, sorted.
+	First  string
+	Second string
+
+	// Request is a concrete path matched by both First and Second.
+	Request string
+
+	// Winner is the path Request resolves to, which is usually one of the pair
+	// but may be a third, more accurate, path.
+	Winner string
+}
+
+// DetectShadowedPaths returns the pairs of value-holding paths of pt with the
+// same number of segments where each segment is either a param in one of the
+// paths or the same in both, e.g. /v1/{resource}/list and /v1/users/list, so
+// that the resolution of their common requests depends on the matching
+// priorities. Pairs are sorted by First, then Second.
+func DetectShadowedPaths(pt *pathtrie.PathTrie) []ShadowPair {
+	paths := valuePaths(pt)
+	segments := make([][]string, len(paths))
+	for idx, path := range paths {
+		segments[idx] = strings.Split(path, pt.PathSeparator)
+	}
+
+	var pairs []ShadowPair
+	for i := range paths {
+		for j := i + 1; j < len(paths); j++ {
+			request, ok := shadowRequest(pt, segments[i], segments[j])
+			if !ok {
+				continue
+			}
+			winner, _, _ := pt.GetPathAndValue(request)
+			pairs = append(pairs, ShadowPair{
+				First:   paths[i],
+				Second:  paths[j],
+				Request: request,
+				Winner:  winner,
+			})
+		}
+	}
+
+	return pairs
+}
+
+// shadowRequest returns a concrete path matched by both a and b, if any.
+func shadowRequest(pt *pathtrie.PathTrie, a, b []string) (string, bool) {
+	if len(a) != len(b) {
+		return "", false
+	}
+
+	request := make([]string, len(a))
+	for idx := range a {
+		aIsParam, bIsParam := pt.IsParamSegment(a[idx]), pt.IsParamSegment(b[idx])
+		switch {
+		case aIsParam && bIsParam:
+			request[idx] = shadowSegment
+		case aIsParam:
+			request[idx] = b[idx]
+		case bIsParam:
+			request[idx] = a[idx]
+		case a[idx] == b[idx] || pt.CaseInsensitive && strings.EqualFold(a[idx], b[idx]):
+			request[idx] = a[idx]
+		default:
+			return "", false
+		}
+	}
+
+	return strings.Join(request, pt.PathSeparator), true
+}
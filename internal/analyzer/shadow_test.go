@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+func TestDetectShadowedPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		trie     *pathtrie.PathTrie
+		expected []ShadowPair
+	}{
+		{
+			name: "concrete path shadows param path",
+			trie: newTrie("/v1/{resource}/list", "/v1/users/list", "/v1/users/{id}"),
+			expected: []ShadowPair{
+				{
+					First:   "/v1/users/list",
+					Second:  "/v1/users/{id}",
+					Request: "/v1/users/list",
+					Winner:  "/v1/users/list",
+				},
+				{
+					First:   "/v1/users/list",
+					Second:  "/v1/{resource}/list",
+					Request: "/v1/users/list",
+					Winner:  "/v1/users/list",
+				},
+				{
+					First:   "/v1/users/{id}",
+					Second:  "/v1/{resource}/list",
+					Request: "/v1/users/list",
+					Winner:  "/v1/users/list",
+				},
+			},
+		},
+		{
+			name: "two-param tie",
+			trie: newTrie("/v1/users/{id}", "/v1/users/{name}"),
+			expected: []ShadowPair{
+				{
+					First:   "/v1/users/{id}",
+					Second:  "/v1/users/{name}",
+					Request: "/v1/users/shadowed",
+					Winner:  "/v1/users/{id}",
+				},
+			},
+		},
+		{
+			name: "param position decides the winner",
+			trie: newTrie("/v1/{tenant}/users/{id}", "/v1/{tenant}/{resource}/42"),
+			expected: []ShadowPair{
+				{
+					First:   "/v1/{tenant}/users/{id}",
+					Second:  "/v1/{tenant}/{resource}/42",
+					Request: "/v1/shadowed/users/42",
+					Winner:  "/v1/{tenant}/users/{id}",
+				},
+			},
+		},
+		{
+			name:     "no overlap",
+			trie:     newTrie("/v1/users/{id}", "/v1/orders/{id}", "/v1/users/{id}/avatar"),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectShadowedPaths(tt.trie))
+		})
+	}
+}
+
+func TestDetectShadowedPaths_IsPathParam(t *testing.T) {
+	// With colon-prefixed params, {id} is a static segment.
+	trie := pathtrie.New()
+	trie.IsPathParam = func(segment string) bool {
+		return strings.HasPrefix(segment, ":")
+	}
+	trie.Insert("/v1/users/:id", 1)
+	trie.Insert("/v1/users/me", 2)
+	trie.Insert("/v1/orders/{id}", 3)
+	trie.Insert("/v1/orders/42", 4)
+
+	assert.Equal(t, []ShadowPair{
+		{
+			First:   "/v1/users/:id",
+			Second:  "/v1/users/me",
+			Request: "/v1/users/me",
+			Winner:  "/v1/users/me",
+		},
+	}, DetectShadowedPaths(&trie))
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+// Retemplated is a spec path whose params moved between two spec versions.
+type Retemplated struct {
+	Old string
+	New string
+
+	// OldParamPositions and NewParamPositions are the segment indices of the
+	// params of Old and New, see pathtrie.PathTrie.ParamPositions.
+	OldParamPositions []int
+	NewParamPositions []int
+}
+
+// SpecDelta lists the path changes between two spec versions.
+type SpecDelta struct {
+	// RemovedPaths are the old paths missing from the new spec, i.e. breaking
+	// changes.
+	RemovedPaths []string
+
+	// AddedPaths are the new paths missing from the old spec.
+	AddedPaths []string
+
+	// RetemplatedPaths are the old paths replaced by a new path with the same
+	// number of segments and params at other positions, which match a common
+	// concrete path, e.g. /v1/users/{id}/profile and /v1/users/me/{section}.
+	// These are breaking changes too, but are not listed as removed nor added.
+	RetemplatedPaths []Retemplated
+}
+
+// DiffSpecVersions compares the value-holding paths of oldSpec and newSpec,
+// expected to use the same path separator. Old and new paths that only differ
+// by the names of their params are considered the same, and a literal path
+// replacing a templated one is a removal plus an addition. RemovedPaths and
+// AddedPaths are sorted, and RetemplatedPaths are sorted by Old.
+func DiffSpecVersions(oldSpec, newSpec *pathtrie.PathTrie) SpecDelta {
+	oldPaths, newPaths := valuePaths(oldSpec), valuePaths(newSpec)
+	removed := withoutPaths(oldPaths, newPaths)
+	added := withoutPaths(newPaths, oldPaths)
+
+	matched := make(map[string]struct{})
+	counterpart := func(oldPath string, accept func(oldPositions, newPositions []int) bool) (string, bool) {
+		oldSegments := strings.Split(oldPath, oldSpec.PathSeparator)
+		oldPositions := oldSpec.ParamPositions(oldPath)
+		for _, newPath := range added {
+			if _, ok := matched[newPath]; ok {
+				continue
+			}
+			if !accept(oldPositions, newSpec.ParamPositions(newPath)) {
+				continue
+			}
+			if _, ok := shadowRequest(oldSpec, oldSegments, strings.Split(newPath, newSpec.PathSeparator)); ok {
+				matched[newPath] = struct{}{}
+				return newPath, true
+			}
+		}
+		return "", false
+	}
+
+	delta := SpecDelta{
+		RemovedPaths:     []string{},
+		AddedPaths:       []string{},
+		RetemplatedPaths: []Retemplated{},
+	}
+	var unmatched []string
+	for _, oldPath := range removed {
+		// Renamed params first, so that they aren't taken for a retemplating.
+		if _, ok := counterpart(oldPath, slices.Equal[[]int]); !ok {
+			unmatched = append(unmatched, oldPath)
+		}
+	}
+	for _, oldPath := range unmatched {
+		newPath, ok := counterpart(oldPath, func(oldPositions, newPositions []int) bool {
+			return len(oldPositions) > 0 && len(newPositions) > 0 && !slices.Equal(oldPositions, newPositions)
+		})
+		if !ok {
+			delta.RemovedPaths = append(delta.RemovedPaths, oldPath)
+			continue
+		}
+		delta.RetemplatedPaths = append(delta.RetemplatedPaths, Retemplated{
+			Old:               oldPath,
+			New:               newPath,
+			OldParamPositions: oldSpec.ParamPositions(oldPath),
+			NewParamPositions: newSpec.ParamPositions(newPath),
+		})
+	}
+	for _, newPath := range added {
+		if _, ok := matched[newPath]; !ok {
+			delta.AddedPaths = append(delta.AddedPaths, newPath)
+		}
+	}
+
+	return delta
+}
+
+// withoutPaths returns the sorted paths that are not in excluded.
+func withoutPaths(paths, excluded []string) []string {
+	excludedSet := make(map[string]struct{}, len(excluded))
+	for _, path := range excluded {
+		excludedSet[path] = struct{}{}
+	}
+
+	var kept []string
+	for _, path := range paths {
+		if _, ok := excludedSet[path]; !ok {
+			kept = append(kept, path)
+		}
+	}
+
+	return kept
+}
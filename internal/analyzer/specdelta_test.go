@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+func TestDiffSpecVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldSpec  *pathtrie.PathTrie
+		newSpec  *pathtrie.PathTrie
+		expected SpecDelta
+	}{
+		{
+			name:    "param moved from segment 3 to segment 4",
+			oldSpec: newTrie("/v1/users", "/v1/users/{id}/profile", "/v1/legacy"),
+			newSpec: newTrie("/v1/users", "/v1/users/me/{section}", "/v1/orders"),
+			expected: SpecDelta{
+				RemovedPaths: []string{"/v1/legacy"},
+				AddedPaths:   []string{"/v1/orders"},
+				RetemplatedPaths: []Retemplated{
+					{
+						Old:               "/v1/users/{id}/profile",
+						New:               "/v1/users/me/{section}",
+						OldParamPositions: []int{3},
+						NewParamPositions: []int{4},
+					},
+				},
+			},
+		},
+		{
+			name:    "renamed param",
+			oldSpec: newTrie("/v1/users/{id}"),
+			newSpec: newTrie("/v1/users/me", "/v1/users/{userId}"),
+			expected: SpecDelta{
+				RemovedPaths:     []string{},
+				AddedPaths:       []string{"/v1/users/me"},
+				RetemplatedPaths: []Retemplated{},
+			},
+		},
+		{
+			name:    "literal replacing a param",
+			oldSpec: newTrie("/v1/users/{id}"),
+			newSpec: newTrie("/v1/users/me"),
+			expected: SpecDelta{
+				RemovedPaths:     []string{"/v1/users/{id}"},
+				AddedPaths:       []string{"/v1/users/me"},
+				RetemplatedPaths: []Retemplated{},
+			},
+		},
+		{
+			name:    "different depth",
+			oldSpec: newTrie("/v1/users/{id}"),
+			newSpec: newTrie("/v1/users/{id}/{section}"),
+			expected: SpecDelta{
+				RemovedPaths:     []string{"/v1/users/{id}"},
+				AddedPaths:       []string{"/v1/users/{id}/{section}"},
+				RetemplatedPaths: []Retemplated{},
+			},
+		},
+		{
+			name:    "same spec",
+			oldSpec: newTrie("/v1/users", "/v1/users/{id}"),
+			newSpec: newTrie("/v1/users", "/v1/users/{id}"),
+			expected: SpecDelta{
+				RemovedPaths:     []string{},
+				AddedPaths:       []string{},
+				RetemplatedPaths: []Retemplated{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DiffSpecVersions(tt.oldSpec, tt.newSpec)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+// UndocumentedSegments is an observed path longer than the spec path it
+// matches the prefix of, which likely is an undocumented sub-resource or param.
+type UndocumentedSegments struct {
+	ObservedPath string
+	SpecPath     string
+
+	// ExtraSegments are the segments of ObservedPath following SpecPath.
+	ExtraSegments []string
+
+	// PathSeparator is the separator of the spec PathTrie, used to join
+	// ExtraSegments, "/" if empty.
+	PathSeparator string
+}
+
+func (u UndocumentedSegments) String() string {
+	return fmt.Sprintf("possible undocumented sub-resource or param `%s` of %s, observed as %s",
+		strings.Join(u.ExtraSegments, cmp.Or(u.PathSeparator, "/")), u.SpecPath, u.ObservedPath)
+}
+
+// DetectUndocumentedSegments returns the observed paths matching no spec path,
+// but whose prefix matches one according to PathTrie.MatchPrefix, sorted by
+// observed path.
+func DetectUndocumentedSegments(spec, observed *pathtrie.PathTrie) []UndocumentedSegments {
+	var results []UndocumentedSegments
+	for _, observedPath := range valuePaths(observed) {
+		if _, _, ok := matchSpecPath(spec, observedPath); ok {
+			continue
+		}
+
+		node, remaining, ok := spec.MatchPrefix(observedPath)
+		if !ok || len(remaining) == 0 {
+			continue
+		}
+		results = append(results, UndocumentedSegments{
+			ObservedPath:  observedPath,
+			SpecPath:      node.FullPath,
+			ExtraSegments: remaining,
+			PathSeparator: spec.PathSeparator,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+func TestDetectUndocumentedSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *pathtrie.PathTrie
+		observed *pathtrie.PathTrie
+		expected []UndocumentedSegments
+	}{
+		{
+			name:     "undocumented sub-resource",
+			spec:     newTrie("/v1/users/{id}"),
+			observed: newTrie("/v1/users/42", "/v1/users/42/sessions"),
+			expected: []UndocumentedSegments{
+				{
+					ObservedPath:  "/v1/users/42/sessions",
+					SpecPath:      "/v1/users/{id}",
+					ExtraSegments: []string{"sessions"},
+					PathSeparator: "/",
+				},
+			},
+		},
+		{
+			name:     "several extra segments",
+			spec:     newTrie("/v1/users/{id}", "/v1/users/{id}/sessions"),
+			observed: newTrie("/v1/users/42/sessions/abc/revoke", "/v1/users/42/sessions"),
+			expected: []UndocumentedSegments{
+				{
+					ObservedPath:  "/v1/users/42/sessions/abc/revoke",
+					SpecPath:      "/v1/users/{id}/sessions",
+					ExtraSegments: []string{"abc", "revoke"},
+					PathSeparator: "/",
+				},
+			},
+		},
+		{
+			name:     "no spec prefix",
+			spec:     newTrie("/v1/users/{id}"),
+			observed: newTrie("/v2/orders/1"),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DetectUndocumentedSegments(tt.spec, tt.observed)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestUndocumentedSegments_String(t *testing.T) {
+	u := UndocumentedSegments{
+		ObservedPath:  "/v1/users/42/sessions/abc",
+		SpecPath:      "/v1/users/{id}",
+		ExtraSegments: []string{"sessions", "abc"},
+	}
+	assert.Equal(t, "possible undocumented sub-resource or param `sessions/abc` of /v1/users/{id}, observed as /v1/users/42/sessions/abc", u.String())
+}
+
+func TestDetectUndocumentedSegments_separator(t *testing.T) {
+	spec := pathtrie.NewWithPathSeparator(".")
+	spec.Insert("com.example.users.{id}", struct{}{})
+	observed := pathtrie.NewWithPathSeparator(".")
+	observed.Insert("com.example.users.42.sessions.abc", struct{}{})
+
+	result := DetectUndocumentedSegments(&spec, &observed)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "possible undocumented sub-resource or param `sessions.abc` of com.example.users.{id}, observed as com.example.users.42.sessions.abc", result[0].String())
+	}
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"time"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+// ZombieOptions configures DetectZombiesWithOptions.
+type ZombieOptions struct {
+	// AddedAt returns when the spec path was added, and false if unknown.
+	AddedAt func(specPath string) (time.Time, bool)
+
+	// MinObservationWindow is how long traffic must have been observed since a
+	// spec path was added, according to AddedAt, for it to be reported. Paths
+	// whose addition time is unknown are always reported.
+	MinObservationWindow time.Duration
+
+	// Now is the end of the observation window, time.Now() if zero.
+	Now time.Time
+}
+
+// DetectZombies returns the sorted spec paths that no observed path matched,
+// i.e. documented endpoints that are candidates for deprecation. Matching is
+// the same as Diff.
+func DetectZombies(spec, observed *pathtrie.PathTrie) []string {
+	return DetectZombiesWithOptions(spec, observed, ZombieOptions{})
+}
+
+// DetectZombiesWithOptions is like DetectZombies but doesn't report the spec
+// paths added less than opts.MinObservationWindow ago.
+func DetectZombiesWithOptions(spec, observed *pathtrie.PathTrie, opts ZombieOptions) []string {
+	zombies := Diff(spec, observed).OnlyInSpec
+	if opts.AddedAt == nil || opts.MinObservationWindow <= 0 {
+		return zombies
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	filtered := []string{}
+	for _, specPath := range zombies {
+		if addedAt, ok := opts.AddedAt(specPath); ok && now.Sub(addedAt) < opts.MinObservationWindow {
+			continue
+		}
+		filtered = append(filtered, specPath)
+	}
+
+	return filtered
+}
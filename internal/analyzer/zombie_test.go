@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectZombies(t *testing.T) {
+	spec := newTrie("/v1/legacy/{id}", "/v1/users/{id}", "/v1/users")
+	observed := newTrie("/v1/users/42", "/v1/users")
+
+	assert.Equal(t, []string{"/v1/legacy/{id}"}, DetectZombies(spec, observed))
+	assert.Equal(t, []string{}, DetectZombies(spec, newTrie("/v1/legacy/1", "/v1/users/1", "/v1/users")))
+}
+
+func TestDetectZombiesWithOptions(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	addedAt := map[string]time.Time{
+		"/v1/legacy/{id}": now.AddDate(0, -6, 0),
+		"/v1/beta/{id}":   now.AddDate(0, 0, -3),
+	}
+	spec := newTrie("/v1/legacy/{id}", "/v1/beta/{id}", "/v1/unknown", "/v1/users")
+	observed := newTrie("/v1/users")
+
+	tests := []struct {
+		name     string
+		opts     ZombieOptions
+		expected []string
+	}{
+		{
+			name:     "no window",
+			opts:     ZombieOptions{},
+			expected: []string{"/v1/beta/{id}", "/v1/legacy/{id}", "/v1/unknown"},
+		},
+		{
+			name: "recently added path is skipped",
+			opts: ZombieOptions{
+				AddedAt: func(specPath string) (time.Time, bool) {
+					at, ok := addedAt[specPath]
+					return at, ok
+				},
+				MinObservationWindow: 30 * 24 * time.Hour,
+				Now:                  now,
+			},
+			expected: []string{"/v1/legacy/{id}", "/v1/unknown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectZombiesWithOptions(spec, observed, tt.opts))
+		})
+	}
+}
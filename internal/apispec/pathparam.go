@@ -6,7 +6,7 @@ import (
 	"strings"
 	"unicode"
 
-	"github.com/gofrs/uuid"
+	"github.com/5gsec/api-speculator/internal/util"
 )
 
 // This code is inspired by the openclarity speculator library
@@ -14,9 +14,102 @@ import (
 
 var digitCheck = regexp.MustCompile(`^[0-9]+$`)
 
-// UnifyParameterizedPathIfApplicable normalizes a path by replacing dynamic segments with {paramN}.
+const (
+	// UUIDPlaceholder replaces the UUID segments of parameterized paths.
+	UUIDPlaceholder = "{uuid}"
+
+	// TokenPlaceholder replaces the hash-like and opaque token segments of
+	// parameterized paths.
+	TokenPlaceholder = "{token}"
+
+	// DatePlaceholder replaces the date segments of parameterized paths.
+	DatePlaceholder = "{date}"
+
+	// CanonicalPlaceholder is the default canonical placeholder of NormalizePath.
+	CanonicalPlaceholder = "{param}"
+)
+
+// ParameterizeOptions configures how UnifyParameterizedPathWithOptions detects
+// and names dynamic segments. Zero values fall back to the defaults.
+type ParameterizeOptions struct {
+	// Placeholder is the name of the params replacing dynamic segments, "param"
+	// by default.
+	Placeholder string
+
+	// Unnumbered disables numbering the params, e.g. {param} instead of
+	// {param1}, {param2}...
+	Unnumbered bool
+
+	// MixedMinLength is the minimum length of a segment mixing digits and chars
+	// to be considered as a param, 8 by default.
+	MixedMinLength int
+
+	// MixedMinDigits is the minimum number of digits of a segment mixing digits
+	// and chars to be considered as a param, 3 by default.
+	MixedMinDigits int
+
+	// TokenMinLength is the minimum length of opaque tokens, see
+	// util.IsOpaqueTokenWithThresholds. Defaults to util.DefaultTokenMinLength.
+	TokenMinLength int
+
+	// TokenMinEntropy is the minimum entropy of opaque tokens, see
+	// util.IsOpaqueTokenWithThresholds. Defaults to util.DefaultTokenMinEntropy.
+	TokenMinEntropy float64
+
+	// DateLayouts are the time layouts of date segments. Defaults to
+	// util.DefaultDateLayouts.
+	DateLayouts []string
+
+	// SplitDates also replaces dates split across three segments, e.g.
+	// /2024/01/15, with a single date placeholder.
+	SplitDates bool
+
+	// CanonicalPlaceholder, if set, replaces every dynamic segment as is,
+	// whether a date, UUID, token or other param, instead of the specific and
+	// numbered placeholders, e.g. {param}. Named spec placeholders are still
+	// kept when isSpec is true.
+	CanonicalPlaceholder string
+}
+
+const (
+	defaultPlaceholder    = "param"
+	defaultMixedMinLength = 8
+	defaultMixedMinDigits = 3
+)
+
+func (opts ParameterizeOptions) withDefaults() ParameterizeOptions {
+	if opts.Placeholder == "" {
+		opts.Placeholder = defaultPlaceholder
+	}
+	if opts.MixedMinLength <= 0 {
+		opts.MixedMinLength = defaultMixedMinLength
+	}
+	if opts.MixedMinDigits <= 0 {
+		opts.MixedMinDigits = defaultMixedMinDigits
+	}
+	if opts.TokenMinLength <= 0 {
+		opts.TokenMinLength = util.DefaultTokenMinLength
+	}
+	if opts.TokenMinEntropy <= 0 {
+		opts.TokenMinEntropy = util.DefaultTokenMinEntropy
+	}
+	if opts.DateLayouts == nil {
+		opts.DateLayouts = util.DefaultDateLayouts
+	}
+	return opts
+}
+
+// UnifyParameterizedPathIfApplicable normalizes a path by replacing date segments with {date}, UUID
+// segments with {uuid}, hash-like and opaque token segments with {token} and other dynamic segments
+// with {paramN}.
 // If isSpec = true, also treats existing {param} segments in OpenAPI specs as parameters.
 func UnifyParameterizedPathIfApplicable(path string, isSpec bool) string {
+	return UnifyParameterizedPathWithOptions(path, isSpec, ParameterizeOptions{})
+}
+
+// UnifyParameterizedPathWithOptions is like UnifyParameterizedPathIfApplicable
+// but with configurable heuristics and placeholder.
+func UnifyParameterizedPathWithOptions(path string, isSpec bool, opts ParameterizeOptions) string {
 	if path == "" {
 		return ""
 	}
@@ -24,20 +117,46 @@ func UnifyParameterizedPathIfApplicable(path string, isSpec bool) string {
 		return "/"
 	}
 
+	opts = opts.withDefaults()
 	pathParts := strings.Split(strings.Trim(path, "/"), "/")
 	var parameterizedPathParts []string
 	paramCount := 0
 
-	for _, part := range pathParts {
+	for idx := 0; idx < len(pathParts); idx++ {
+		part := pathParts[idx]
 		if isSpec && strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
 			parameterizedPathParts = append(parameterizedPathParts, part)
 			continue
 		}
 
-		if isSuspectPathParam(part) {
+		if opts.SplitDates && idx+2 < len(pathParts) && util.IsSplitDateSegments(part, pathParts[idx+1], pathParts[idx+2]) {
+			parameterizedPathParts = append(parameterizedPathParts, opts.placeholder(DatePlaceholder))
+			idx += 2
+			continue
+		}
+
+		if util.IsDateSegmentWithLayouts(part, opts.DateLayouts) {
+			parameterizedPathParts = append(parameterizedPathParts, opts.placeholder(DatePlaceholder))
+			continue
+		}
+
+		if !isNumber(part) && util.IsUUID(part) {
+			parameterizedPathParts = append(parameterizedPathParts, opts.placeholder(UUIDPlaceholder))
+			continue
+		}
+
+		if util.IsOpaqueTokenWithThresholds(part, opts.TokenMinLength, opts.TokenMinEntropy) {
+			parameterizedPathParts = append(parameterizedPathParts, opts.placeholder(TokenPlaceholder))
+			continue
+		}
+
+		if isSuspectPathParam(part, opts) {
 			paramCount++
-			paramName := fmt.Sprintf("param%v", paramCount)
-			parameterizedPathParts = append(parameterizedPathParts, "{"+paramName+"}")
+			paramName := opts.Placeholder
+			if !opts.Unnumbered {
+				paramName = fmt.Sprintf("%s%v", opts.Placeholder, paramCount)
+			}
+			parameterizedPathParts = append(parameterizedPathParts, opts.placeholder("{"+paramName+"}"))
 		} else {
 			parameterizedPathParts = append(parameterizedPathParts, part)
 		}
@@ -45,31 +164,47 @@ func UnifyParameterizedPathIfApplicable(path string, isSpec bool) string {
 	return "/" + strings.Join(parameterizedPathParts, "/")
 }
 
-func isSuspectPathParam(part string) bool {
-	return isNumber(part) || isUUID(part) || isMixed(part)
+// placeholder returns the canonical placeholder if set, otherwise the specific
+// one.
+func (opts ParameterizeOptions) placeholder(specific string) string {
+	if opts.CanonicalPlaceholder != "" {
+		return opts.CanonicalPlaceholder
+	}
+
+	return specific
 }
 
-func isNumber(s string) bool {
-	return digitCheck.MatchString(s)
+// NormalizePath replaces the dynamic segments of path with canonicalPlaceholder,
+// CanonicalPlaceholder if empty, so that spec and traffic paths align whatever
+// the kind of their dynamic segments. If isSpec is true, the named placeholders
+// of the spec path, e.g. {userId}, are kept.
+func NormalizePath(path string, isSpec bool, canonicalPlaceholder string) string {
+	if canonicalPlaceholder == "" {
+		canonicalPlaceholder = CanonicalPlaceholder
+	}
+
+	return UnifyParameterizedPathWithOptions(path, isSpec, ParameterizeOptions{
+		CanonicalPlaceholder: canonicalPlaceholder,
+	})
 }
 
-func isUUID(s string) bool {
-	_, err := uuid.FromString(s)
-	return err == nil
+func isSuspectPathParam(part string, opts ParameterizeOptions) bool {
+	return isNumber(part) || isMixed(part, opts.MixedMinLength, opts.MixedMinDigits)
 }
 
-// Check if a path part that is mixed from digits and chars can be considered as
-// parameter following hard-coded heuristics. Temporary, we'll consider strings
-// as parameters that are at least 8 chars longs and has at least 3 digits.
-func isMixed(pathPart string) bool {
-	const maxLen = 8
-	const minDigitsLen = 2
+func isNumber(s string) bool {
+	return digitCheck.MatchString(s)
+}
 
-	if len(pathPart) < maxLen {
+// Check if a path part that is mixed from digits and chars can be considered as
+// parameter following heuristics. By default, we'll consider strings as
+// parameters that are at least 8 chars longs and has at least 3 digits.
+func isMixed(pathPart string, minLen, minDigits int) bool {
+	if len(pathPart) < minLen {
 		return false
 	}
 
-	return countDigitsInString(pathPart) > minDigitsLen
+	return countDigitsInString(pathPart) >= minDigits
 }
 
 func countDigitsInString(s string) int {
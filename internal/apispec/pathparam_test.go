@@ -29,6 +29,36 @@ func TestUnifyParameterizedPathIfApplicable(t *testing.T) {
 			name:     "UUID in path",
 			input:    "/orders/550e8400-e29b-41d4-a716-446655440000",
 			isSpec:   false,
+			expected: "/orders/{uuid}",
+		},
+		{
+			name:     "uppercase UUID in path",
+			input:    "/tenants/F47AC10B-58CC-4372-A567-0E02B2C3D479",
+			isSpec:   false,
+			expected: "/tenants/{uuid}",
+		},
+		{
+			name:     "hyphenless UUID in path",
+			input:    "/tenants/f47ac10b58cc4372a5670e02b2c3d479/users",
+			isSpec:   false,
+			expected: "/tenants/{uuid}/users",
+		},
+		{
+			name:     "near-miss UUID with digits is a mixed param",
+			input:    "/orders/550e8400-e29b-41d4-a716-44665544000g",
+			isSpec:   false,
+			expected: "/orders/{param1}",
+		},
+		{
+			name:     "near-miss UUID without digits (not treated as param)",
+			input:    "/orders/abcdefab-cdef-abcd-efab-cdefabcdefag",
+			isSpec:   false,
+			expected: "/orders/abcdefab-cdef-abcd-efab-cdefabcdefag",
+		},
+		{
+			name:     "32 digits number is not a UUID",
+			input:    "/orders/12345678901234567890123456789012",
+			isSpec:   false,
 			expected: "/orders/{param1}",
 		},
 		{
@@ -37,6 +67,48 @@ func TestUnifyParameterizedPathIfApplicable(t *testing.T) {
 			isSpec:   false,
 			expected: "/data/{param1}",
 		},
+		{
+			name:     "hash in path",
+			input:    "/cache/9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08/data",
+			isSpec:   false,
+			expected: "/cache/{token}/data",
+		},
+		{
+			name:     "base64url token in path",
+			input:    "/sessions/eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9",
+			isSpec:   false,
+			expected: "/sessions/{token}",
+		},
+		{
+			name:     "ISO date in path",
+			input:    "/metrics/2024-01-15/cpu",
+			isSpec:   false,
+			expected: "/metrics/{date}/cpu",
+		},
+		{
+			name:     "basic ISO date in path",
+			input:    "/metrics/20240115/cpu",
+			isSpec:   false,
+			expected: "/metrics/{date}/cpu",
+		},
+		{
+			name:     "not a date",
+			input:    "/releases/2024-version",
+			isSpec:   false,
+			expected: "/releases/{param1}",
+		},
+		{
+			name:     "split date is not a date by default",
+			input:    "/metrics/2024/01/15/cpu",
+			isSpec:   false,
+			expected: "/metrics/{param1}/{param2}/{param3}/cpu",
+		},
+		{
+			name:     "long word (not treated as param)",
+			input:    "/v1/internationalization",
+			isSpec:   false,
+			expected: "/v1/internationalization",
+		},
 		{
 			name:     "short alphanumeric (not treated as param)",
 			input:    "/data/ab12",
@@ -47,7 +119,19 @@ func TestUnifyParameterizedPathIfApplicable(t *testing.T) {
 			name:     "multiple parameters in path",
 			input:    "/users/123/orders/550e8400-e29b-41d4-a716-446655440000",
 			isSpec:   false,
-			expected: "/users/{param1}/orders/{param2}",
+			expected: "/users/{param1}/orders/{uuid}",
+		},
+		{
+			name:     "numeric parameters around a uuid",
+			input:    "/users/123/orders/550e8400-e29b-41d4-a716-446655440000/items/7",
+			isSpec:   false,
+			expected: "/users/{param1}/orders/{uuid}/items/{param2}",
+		},
+		{
+			name:     "numeric segments between static ones",
+			input:    "/v1/42/orders/7",
+			isSpec:   false,
+			expected: "/v1/{param1}/orders/{param2}",
 		},
 		{
 			name:     "already parameterized spec path",
@@ -82,3 +166,140 @@ func TestUnifyParameterizedPathIfApplicable(t *testing.T) {
 		})
 	}
 }
+
+func TestUnifyParameterizedPathWithOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		isSpec   bool
+		opts     ParameterizeOptions
+		expected string
+	}{
+		{
+			name:     "default options",
+			input:    "/v1/42/orders/7",
+			isSpec:   false,
+			opts:     ParameterizeOptions{},
+			expected: "/v1/{param1}/orders/{param2}",
+		},
+		{
+			name:     "custom placeholder",
+			input:    "/v1/42/orders/7",
+			isSpec:   false,
+			opts:     ParameterizeOptions{Placeholder: "id"},
+			expected: "/v1/{id1}/orders/{id2}",
+		},
+		{
+			name:     "unnumbered placeholder",
+			input:    "/v1/orders/99812",
+			isSpec:   false,
+			opts:     ParameterizeOptions{Placeholder: "id", Unnumbered: true},
+			expected: "/v1/orders/{id}",
+		},
+		{
+			name:     "spec placeholders are preserved",
+			input:    "/v1/{orderId}/items/7",
+			isSpec:   true,
+			opts:     ParameterizeOptions{Placeholder: "id"},
+			expected: "/v1/{orderId}/items/{id1}",
+		},
+		{
+			name:     "lower mixed thresholds",
+			input:    "/data/ab12",
+			isSpec:   false,
+			opts:     ParameterizeOptions{MixedMinLength: 4, MixedMinDigits: 2},
+			expected: "/data/{param1}",
+		},
+		{
+			name:     "higher mixed thresholds",
+			input:    "/data/abc12345xyz",
+			isSpec:   false,
+			opts:     ParameterizeOptions{MixedMinLength: 16},
+			expected: "/data/abc12345xyz",
+		},
+		{
+			name:     "custom date layouts",
+			input:    "/metrics/15.01.2024/2024-01-15",
+			isSpec:   false,
+			opts:     ParameterizeOptions{DateLayouts: []string{"02.01.2006"}},
+			expected: "/metrics/{date}/{param1}",
+		},
+		{
+			name:     "split dates",
+			input:    "/metrics/2024/01/15/cpu/7",
+			isSpec:   false,
+			opts:     ParameterizeOptions{SplitDates: true},
+			expected: "/metrics/{date}/cpu/{param1}",
+		},
+		{
+			name:     "split dates with invalid date",
+			input:    "/metrics/2024/13/15",
+			isSpec:   false,
+			opts:     ParameterizeOptions{SplitDates: true},
+			expected: "/metrics/{param1}/{param2}/{param3}",
+		},
+		{
+			name:     "higher token min length",
+			input:    "/cache/a94a8fe5ccb19ba61c4c0873d391e987982fbbd3",
+			isSpec:   false,
+			opts:     ParameterizeOptions{TokenMinLength: 64},
+			expected: "/cache/{param1}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := UnifyParameterizedPathWithOptions(tt.input, tt.isSpec, tt.opts)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		name                 string
+		input                string
+		isSpec               bool
+		canonicalPlaceholder string
+		expected             string
+	}{
+		{
+			name:     "spec keeps named placeholders",
+			input:    "/v1/users/{userId}/posts/{postId}",
+			isSpec:   true,
+			expected: "/v1/users/{userId}/posts/{postId}",
+		},
+		{
+			name:     "traffic collapses to canonical placeholder",
+			input:    "/v1/users/123/posts/456",
+			isSpec:   false,
+			expected: "/v1/users/{param}/posts/{param}",
+		},
+		{
+			name:     "every kind of dynamic segment collapses",
+			input:    "/v1/orders/550e8400-e29b-41d4-a716-446655440000/2024-01-15/aB3dE5fG7hJ9kL1mN3pQ",
+			isSpec:   false,
+			expected: "/v1/orders/{param}/{param}/{param}",
+		},
+		{
+			name:     "named and generic placeholders at the same depth",
+			input:    "/v1/users/{userId}/posts/456",
+			isSpec:   true,
+			expected: "/v1/users/{userId}/posts/{param}",
+		},
+		{
+			name:                 "custom canonical placeholder",
+			input:                "/v1/users/123",
+			isSpec:               false,
+			canonicalPlaceholder: ":id",
+			expected:             "/v1/users/:id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NormalizePath(tt.input, tt.isSpec, tt.canonicalPlaceholder)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
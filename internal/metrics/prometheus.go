@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+// Package metrics exports the pathtrie.Metrics events to monitoring systems.
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+// Prometheus is a pathtrie.Metrics updating Prometheus collectors:
+//   - <namespace>_pathtrie_matches_total, by result "hit" or "miss"
+//   - <namespace>_pathtrie_inserts_total, by result "new" or "existing"
+//   - <namespace>_pathtrie_truncated_matches_total
+//   - <namespace>_pathtrie_nodes, the node count
+type Prometheus struct {
+	matches          *prometheus.CounterVec
+	inserts          *prometheus.CounterVec
+	truncatedMatches prometheus.Counter
+	nodes            prometheus.Gauge
+}
+
+var _ pathtrie.Metrics = (*Prometheus)(nil)
+
+// NewPrometheus creates a Prometheus metrics adapter and registers its
+// collectors with registerer.
+func NewPrometheus(registerer prometheus.Registerer, namespace string) (*Prometheus, error) {
+	p := &Prometheus{
+		matches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "pathtrie",
+			Name:      "matches_total",
+			Help:      "Number of path lookups, by result.",
+		}, []string{"result"}),
+		inserts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "pathtrie",
+			Name:      "inserts_total",
+			Help:      "Number of path insertions, by whether the path is new.",
+		}, []string{"result"}),
+		truncatedMatches: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "pathtrie",
+			Name:      "truncated_matches_total",
+			Help:      "Number of path lookups that stopped collecting candidates.",
+		}),
+		nodes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "pathtrie",
+			Name:      "nodes",
+			Help:      "Number of nodes of the trie.",
+		}),
+	}
+
+	for _, collector := range []prometheus.Collector{p.matches, p.inserts, p.truncatedMatches, p.nodes} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, fmt.Errorf("failed to register path trie metrics: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Prometheus) IncMatch(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	p.matches.WithLabelValues(result).Inc()
+}
+
+func (p *Prometheus) IncInsert(isNew bool) {
+	result := "existing"
+	if isNew {
+		result = "new"
+	}
+	p.inserts.WithLabelValues(result).Inc()
+}
+
+func (p *Prometheus) IncTruncatedMatch() {
+	p.truncatedMatches.Inc()
+}
+
+func (p *Prometheus) SetNodeCount(n int) {
+	p.nodes.Set(float64(n))
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+func TestPrometheus(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := NewPrometheus(registry, "speculator")
+	require.NoError(t, err)
+
+	trie := pathtrie.New()
+	trie.Metrics = metrics
+	trie.MaxCandidates = 1
+	trie.Insert("/v1/users/{name}", 0)
+	trie.Insert("/v1/users/{id}", 1)
+	trie.Insert("/v1/users/{id}", 2)
+	_ = trie.GetValue("/v1/users/42")
+	_ = trie.GetValue("/v1/orders")
+	_ = trie.GetValue("/v1/orders/1")
+
+	expected := `
+# HELP speculator_pathtrie_inserts_total Number of path insertions, by whether the path is new.
+# TYPE speculator_pathtrie_inserts_total counter
+speculator_pathtrie_inserts_total{result="existing"} 1
+speculator_pathtrie_inserts_total{result="new"} 2
+# HELP speculator_pathtrie_matches_total Number of path lookups, by result.
+# TYPE speculator_pathtrie_matches_total counter
+speculator_pathtrie_matches_total{result="hit"} 1
+speculator_pathtrie_matches_total{result="miss"} 2
+# HELP speculator_pathtrie_nodes Number of nodes of the trie.
+# TYPE speculator_pathtrie_nodes gauge
+speculator_pathtrie_nodes 5
+# HELP speculator_pathtrie_truncated_matches_total Number of path lookups that stopped collecting candidates.
+# TYPE speculator_pathtrie_truncated_matches_total counter
+speculator_pathtrie_truncated_matches_total 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected)))
+}
+
+func TestNewPrometheus_alreadyRegistered(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	_, err := NewPrometheus(registry, "speculator")
+	require.NoError(t, err)
+
+	_, err = NewPrometheus(registry, "speculator")
+	assert.ErrorContains(t, err, "failed to register path trie metrics")
+}
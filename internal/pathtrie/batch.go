@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// InsertBatch inserts each of vals at the path of the same index, like as many
+// Insert calls. Paths are inserted in sorted order so that the descent along the
+// prefix shared with the previous path is reused rather than walked again from
+// the root. If a path appears several times, the value of its last occurrence
+// wins. An error is returned, and nothing is inserted, if paths and vals don't
+// have the same length, ErrLengthMismatch, or if any path is rejected by the
+// insertion guards, see TryInsertMerge. Paths skipped by IncludePrefixes or
+// ExcludePrefixes are ignored.
+func (pt *PathTrie) InsertBatch(paths []string, vals []any) error {
+	if len(paths) != len(vals) {
+		return fmt.Errorf("cannot insert %d paths with %d values: %w", len(paths), len(vals), ErrLengthMismatch)
+	}
+
+	order := make([]int, 0, len(paths))
+	// Split once, the segments are reused by the insertion below.
+	pathSegments := make([][]string, len(paths))
+	for idx, path := range paths {
+		segments := pt.splitPath(path)
+		if pt.isFiltered(segments) {
+			continue
+		}
+		if err := pt.validatePath(path, segments); err != nil {
+			return err
+		}
+		order = append(order, idx)
+		pathSegments[idx] = segments
+	}
+	// Keep the order of duplicated paths, so that the last value wins.
+	slices.SortStableFunc(order, func(a, b int) int {
+		return strings.Compare(paths[a], paths[b])
+	})
+
+	overwrite := func(existing, newV *any) {
+		*existing = *newV
+	}
+
+	var prevSegments []string
+	// tries[idx] holds the node of the segment idx of the previous path.
+	tries := []PathToTrieNode{pt.Trie}
+	for _, idx := range order {
+		segments := pathSegments[idx]
+
+		// Resume the descent from the deepest node shared with the previous path,
+		// but always insert at least the last segment.
+		shared := 0
+		for shared < len(prevSegments) && shared < len(segments)-1 &&
+			pt.nodeKey(segments[shared]) == pt.nodeKey(prevSegments[shared]) &&
+			// Descend again to record the name of a differently named param.
+			!(pt.KeepParamNames && pt.isUnifiable(segments[shared])) {
+			// Keep the existing node name, as insertSegments does.
+			segments[shared] = prevSegments[shared]
+			shared++
+		}
+		// The inner segments of compressed nodes have no children map to resume
+		// from.
+		for tries[shared] == nil {
+			shared--
+		}
+
+		_, tries = pt.insertSegments(tries[:shared+1], segments, vals[idx], overwrite)
+		prevSegments = segments
+	}
+
+	return nil
+}
+
+// ContainsAll reports, for each of paths, whether a value-holding node matches
+// it, like as many GetValue calls, with path params matching any segment, e.g.
+// to check which spec paths were observed. Paths normalizing to the same
+// segments are only looked up once.
+func (pt *PathTrie) ContainsAll(paths []string) map[string]bool {
+	found := make(map[string]bool, len(paths))
+	// Keyed by the canonical path, as several raw paths may map to it.
+	normalized := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if _, ok := found[path]; ok {
+			continue
+		}
+
+		key := pt.Normalize(path)
+		present, ok := normalized[key]
+		if !ok {
+			present = pt.getNode(path) != nil
+			normalized[key] = present
+		}
+		found[path] = present
+	}
+
+	return found
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestPathTrie_InsertBatch(t *testing.T) {
+	paths := []string{
+		"/v1/users/{id}/posts",
+		"/v1/users",
+		"/v2/orders/",
+		"/v1/users/{id}",
+		"/v1/users",
+		"/v1/users/{id}/posts/{postId}",
+		"/v1",
+	}
+	vals := []any{1, 2, 3, 4, 5, 6, 7}
+
+	want := New()
+	for idx, path := range paths {
+		want.Insert(path, vals[idx])
+	}
+
+	got := New()
+	if err := got.InsertBatch(paths, vals); err != nil {
+		t.Fatalf("InsertBatch() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Trie, want.Trie) {
+		t.Errorf("InsertBatch() Trie = %v, want %v", marshal(got.Trie), marshal(want.Trie))
+	}
+	if v := got.GetValue("/v1/users"); v != 5 {
+		t.Errorf("GetValue(/v1/users) = %v, want 5", v)
+	}
+}
+
+func TestPathTrie_InsertBatch_caseInsensitive(t *testing.T) {
+	paths := []string{"/v1/Users/{id}", "/V1/users/{id}/posts", "/v1/users"}
+	vals := []any{1, 2, 3}
+
+	want := New()
+	want.CaseInsensitive = true
+	for idx, path := range paths {
+		want.Insert(path, vals[idx])
+	}
+
+	got := New()
+	got.CaseInsensitive = true
+	if err := got.InsertBatch(paths, vals); err != nil {
+		t.Fatalf("InsertBatch() error = %v", err)
+	}
+	// Nodes keep the casing of the first inserted path, which depends on the
+	// insertion order, so only compare the structure.
+	if gotChildren, wantChildren := len(got.GetChildren()), len(want.GetChildren()); gotChildren != wantChildren {
+		t.Errorf("InsertBatch() created %d nodes, want %d", gotChildren, wantChildren)
+	}
+	for idx, path := range paths {
+		if v := got.GetValue(path); v != vals[idx] {
+			t.Errorf("GetValue(%s) = %v, want %v", path, v, vals[idx])
+		}
+	}
+	for _, node := range []string{"/V1/users/{id}/posts", "/V1/users/{id}"} {
+		if fullPath, _, _ := got.GetPathAndValue(node); fullPath != node {
+			t.Errorf("GetPathAndValue(%s) FullPath = %v, want %v", node, fullPath, node)
+		}
+	}
+}
+
+func TestPathTrie_InsertBatch_lengthMismatch(t *testing.T) {
+	pt := New()
+	if err := pt.InsertBatch([]string{"/v1", "/v2"}, []any{1}); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("InsertBatch() error = %v, want %v", err, ErrLengthMismatch)
+	}
+	if len(pt.Trie) != 0 {
+		t.Errorf("Trie = %v, want empty", marshal(pt.Trie))
+	}
+}
+
+func newBenchmarkBatch(n int) ([]string, []any) {
+	paths := make([]string, n)
+	vals := make([]any, n)
+	for i := 0; i < n; i++ {
+		paths[i] = fmt.Sprintf("/api/internal/v1/service%d/resources/%d", i%10, i)
+		vals[i] = i
+	}
+	return paths, vals
+}
+
+func BenchmarkPathTrie_Insert(b *testing.B) {
+	paths, vals := newBenchmarkBatch(100_000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		pt := New()
+		for idx, path := range paths {
+			pt.Insert(path, vals[idx])
+		}
+	}
+}
+
+func BenchmarkPathTrie_InsertBatch(b *testing.B) {
+	paths, vals := newBenchmarkBatch(100_000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		pt := New()
+		if err := pt.InsertBatch(paths, vals); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestPathTrie_ContainsAll(t *testing.T) {
+	pt := New()
+	pt.TrimTrailingSeparator = true
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/users", value: 2},
+		pathAndValue{path: "/v2/orders/{id}/items", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		paths []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want map[string]bool
+	}{
+		{
+			name: "no paths",
+			args: args{
+				paths: nil,
+			},
+			want: map[string]bool{},
+		},
+		{
+			name: "literal and templated paths",
+			args: args{
+				paths: []string{"/v1/users", "/v1/users/42", "/v1/users/{id}", "/v2/orders/7/items"},
+			},
+			want: map[string]bool{
+				"/v1/users":          true,
+				"/v1/users/42":       true,
+				"/v1/users/{id}":     true,
+				"/v2/orders/7/items": true,
+			},
+		},
+		{
+			name: "missing paths",
+			args: args{
+				paths: []string{"/v1", "/v2/orders/7", "/v3/users"},
+			},
+			want: map[string]bool{
+				"/v1":          false,
+				"/v2/orders/7": false,
+				"/v3/users":    false,
+			},
+		},
+		{
+			name: "duplicated and equivalent paths",
+			args: args{
+				paths: []string{"/v1/users", "/v1/users/", "/v1/users", "/v1/orders/", "/v1/orders"},
+			},
+			want: map[string]bool{
+				"/v1/users":   true,
+				"/v1/users/":  true,
+				"/v1/orders/": false,
+				"/v1/orders":  false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pt.ContainsAll(tt.args.paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ContainsAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
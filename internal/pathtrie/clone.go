@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"slices"
+)
+
+// Clone returns a deep copy of the PathTrie, so that mutating the clone doesn't
+// affect pt. Every node is copied, but values are copied by reference: mutating
+// a pointer or map value in place is visible from both tries.
+func (pt *PathTrie) Clone() *PathTrie {
+	clone := *pt
+	clone.Trie = pt.Trie.clone()
+	clone.freeNodes = nil
+	return &clone
+}
+
+func (trie PathToTrieNode) clone() PathToTrieNode {
+	clone := make(PathToTrieNode, len(trie))
+	for segment, node := range trie {
+		nodeClone := *node
+		nodeClone.Children = node.Children.clone()
+		nodeClone.ParamNames = slices.Clone(node.ParamNames)
+		nodeClone.observedValues = slices.Clone(node.observedValues)
+		clone[segment] = &nodeClone
+	}
+	return clone
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathTrie_Clone(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/users/{id}/posts", value: 2},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	clone := pt.Clone()
+	if clone.PathSeparator != pt.PathSeparator {
+		t.Errorf("PathSeparator = %v, want %v", clone.PathSeparator, pt.PathSeparator)
+	}
+	if !reflect.DeepEqual(clone.Trie, pt.Trie) {
+		t.Fatalf("Clone() = %v, want %v", marshal(clone.Trie), marshal(pt.Trie))
+	}
+
+	clone.Insert("/v1/orders", 3)
+	clone.Insert("/v1/users/{id}", 4)
+	clone.Delete("/v1/users/{id}/posts")
+
+	wantValues := map[string]any{
+		"/v1/users/{id}":       1,
+		"/v1/users/{id}/posts": 2,
+		"/v1/orders":           nil,
+	}
+	for path, want := range wantValues {
+		if got := pt.GetValue(path); !reflect.DeepEqual(got, want) {
+			t.Errorf("original GetValue(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestPathTrie_Clone_nilTrie(t *testing.T) {
+	pt := &PathTrie{PathSeparator: "/"}
+
+	clone := pt.Clone()
+	if clone.Trie == nil {
+		t.Fatalf("Clone() Trie = nil, want initialized trie")
+	}
+	if !clone.Insert("/v1/users", 1) {
+		t.Errorf("Insert() = false, want true")
+	}
+	if pt.Trie != nil {
+		t.Errorf("original Trie = %v, want nil", marshal(pt.Trie))
+	}
+}
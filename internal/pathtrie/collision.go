@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"slices"
+)
+
+// reportCollisions calls OnParamStaticCollision for each sibling in trie of
+// node, about to be added, that is a path param while node is static, or the
+// other way around. Siblings are reported in FullPath order.
+func (pt *PathTrie) reportCollisions(trie PathToTrieNode, node *TrieNode) {
+	if pt.OnParamStaticCollision == nil || node.Name == "" {
+		return
+	}
+
+	isParam := pt.isPathParam(node.Name)
+	var siblings []string
+	for _, sibling := range trie {
+		// Skip the end of path markers, and the root of absolute paths.
+		if sibling.Name == "" || pt.isPathParam(sibling.Name) == isParam {
+			continue
+		}
+		siblings = append(siblings, sibling.FullPath)
+	}
+	slices.Sort(siblings)
+
+	for _, sibling := range siblings {
+		if isParam {
+			pt.OnParamStaticCollision(node.FullPath, sibling)
+		} else {
+			pt.OnParamStaticCollision(sibling, node.FullPath)
+		}
+	}
+}
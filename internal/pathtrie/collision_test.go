@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathTrie_OnParamStaticCollision(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  [][2]string
+	}{
+		{
+			name:  "static after param",
+			paths: []string{"/v1/users/{id}", "/v1/users/me"},
+			want:  [][2]string{{"/v1/users/{id}", "/v1/users/me"}},
+		},
+		{
+			name:  "param after statics",
+			paths: []string{"/v1/users/me", "/v1/users/admins/{id}", "/v1/users/{id}"},
+			want: [][2]string{
+				{"/v1/users/{id}", "/v1/users/admins"},
+				{"/v1/users/{id}", "/v1/users/me"},
+			},
+		},
+		{
+			name:  "existing siblings",
+			paths: []string{"/v1/users/{id}", "/v1/users/me", "/v1/users/me/posts", "/v1/users/{id}/posts"},
+			want:  [][2]string{{"/v1/users/{id}", "/v1/users/me"}},
+		},
+		{
+			name:  "params only",
+			paths: []string{"/v1/users/{id}", "/v1/users/*", "/v1/users/", "/v1/{resource}"},
+			want:  [][2]string{{"/v1/{resource}", "/v1/users"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][2]string
+			pt := New()
+			pt.OnParamStaticCollision = func(paramPath, staticPath string) {
+				got = append(got, [2]string{paramPath, staticPath})
+			}
+			for _, path := range tt.paths {
+				pt.Insert(path, path)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OnParamStaticCollision() calls = %v, want %v", got, tt.want)
+			}
+			// The matching is unchanged.
+			for _, path := range tt.paths {
+				if gotVal := pt.GetValue(path); gotVal != path {
+					t.Errorf("GetValue(%s) = %v, want %v", path, gotVal, path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import "strings"
+
+// CompactedParam is the name of the path param nodes created by
+// CompactHighCardinality.
+const CompactedParam = "{param}"
+
+// CompactHighCardinality collapses the static children of every node having more
+// than threshold of them into a single path param node, merging their subtrees.
+// An existing path param child is reused, otherwise a CompactedParam node is
+// created, or a Wildcard one if IsPathParam doesn't recognize CompactedParam.
+// Values of paths present in several merged subtrees are combined with merge,
+// or overwritten if merge is nil. Returns the number of collapsed nodes.
+// Compressed nodes are expanded first, see Compress.
+func (pt *PathTrie) CompactHighCardinality(threshold int, merge ValueMergeFunc) int {
+	pt.expandAll(pt.Trie)
+	collapses := pt.compact(pt.Trie, "", 0, true, threshold, mergeOrOverwrite(merge))
+	pt.invalidateNodeCount()
+
+	return collapses
+}
+
+func (pt *PathTrie) compact(trie PathToTrieNode, parentFullPath string, parentPathParamCounter int, isRoot bool,
+	threshold int, merge ValueMergeFunc) int {
+	collapses := 0
+
+	var static []string
+	var param *TrieNode
+	for key, node := range trie {
+		switch {
+		case node.Name == "":
+			// Skip the end of path markers, and the root of absolute paths.
+		case pt.isPathParam(node.Name):
+			if param == nil || node.Name < param.Name {
+				param = node
+			}
+		default:
+			static = append(static, key)
+		}
+	}
+
+	if len(static) > threshold {
+		if param == nil {
+			name := pt.genericParam()
+			param = &TrieNode{
+				Children:         make(PathToTrieNode),
+				Name:             name,
+				FullPath:         pt.childFullPath(parentFullPath, name, isRoot),
+				PathParamCounter: parentPathParamCounter + 1,
+			}
+			param.SegmentCount = countSegments(strings.Split(param.FullPath, pt.PathSeparator))
+			trie[pt.nodeKey(name)] = param
+		}
+
+		for _, key := range static {
+			pt.mergeNode(param, trie[key], merge)
+			delete(trie, key)
+			collapses++
+		}
+	}
+
+	for _, node := range trie {
+		if node.Name == "" && !isRoot {
+			continue
+		}
+		collapses += pt.compact(node.Children, node.FullPath, node.PathParamCounter, false, threshold, merge)
+	}
+
+	return collapses
+}
+
+// mergeNode merges the value and children of src into dst, rewriting the
+// FullPath, PathParamCounter and SegmentCount of the src descendants to be under
+// dst.
+func (pt *PathTrie) mergeNode(dst, src *TrieNode, merge ValueMergeFunc) {
+	switch {
+	case src.Value == nil:
+	case dst.Value == nil:
+		dst.Value = src.Value
+	default:
+		merge(&dst.Value, &src.Value)
+	}
+	dst.Hits += src.Hits
+	dst.LastSeen = max(dst.LastSeen, src.LastSeen)
+
+	for key, srcChild := range src.Children {
+		if dstChild, ok := dst.Children[key]; ok {
+			pt.mergeNode(dstChild, srcChild, merge)
+			continue
+		}
+
+		pt.reparent(srcChild, dst)
+		dst.Children[key] = srcChild
+	}
+}
+
+// reparent rewrites the FullPath, PathParamCounter and SegmentCount of node and
+// its descendants to be under parent.
+func (pt *PathTrie) reparent(node, parent *TrieNode) {
+	node.FullPath = pt.childFullPath(parent.FullPath, node.Name, false)
+	node.PathParamCounter = parent.PathParamCounter
+	node.SegmentCount = countSegments(strings.Split(node.FullPath, pt.PathSeparator))
+	if pt.isPathParam(node.Name) {
+		node.PathParamCounter++
+	}
+
+	for _, child := range node.Children {
+		pt.reparent(child, node)
+	}
+}
+
+// childFullPath returns the FullPath of a child named name of a parent whose
+// FullPath is parentFullPath, or of a root node if isRoot.
+func (pt *PathTrie) childFullPath(parentFullPath, name string, isRoot bool) string {
+	if isRoot {
+		return name
+	}
+
+	return parentFullPath + pt.PathSeparator + name
+}
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPathTrie_CompactHighCardinality(t *testing.T) {
+	sumMerge := func(existing, newV *any) {
+		*existing = (*existing).(int) + (*newV).(int)
+	}
+	siblings := func(n int) []pathAndValue {
+		var paths []pathAndValue
+		for i := 1; i <= n; i++ {
+			paths = append(paths, pathAndValue{path: fmt.Sprintf("/v1/users/%d", 1000+i), value: i})
+		}
+		return paths
+	}
+
+	type args struct {
+		threshold int
+	}
+	tests := []struct {
+		name         string
+		paths        []pathAndValue
+		args         args
+		want         int
+		wantChildren []string
+		wantValues   map[string]any
+	}{
+		{
+			name:  "3 siblings at threshold 5",
+			paths: siblings(3),
+			args: args{
+				threshold: 5,
+			},
+			want:         0,
+			wantChildren: []string{"/v1", "/v1/users", "/v1/users/1001", "/v1/users/1002", "/v1/users/1003"},
+			wantValues: map[string]any{
+				"/v1/users/1001": 1,
+			},
+		},
+		{
+			name:  "6 siblings at threshold 5",
+			paths: siblings(6),
+			args: args{
+				threshold: 5,
+			},
+			want:         6,
+			wantChildren: []string{"/v1", "/v1/users", "/v1/users/{param}"},
+			wantValues: map[string]any{
+				"/v1/users/{param}": 21,
+				"/v1/users/1001":    21,
+			},
+		},
+		{
+			name: "subtrees are merged",
+			paths: append(siblings(6),
+				pathAndValue{path: "/v1/users/1001/posts", value: 10},
+				pathAndValue{path: "/v1/users/1002/posts", value: 20},
+				pathAndValue{path: "/v1/users/1003/posts/{id}", value: 30},
+			),
+			args: args{
+				threshold: 5,
+			},
+			want: 6,
+			wantChildren: []string{
+				"/v1",
+				"/v1/users",
+				"/v1/users/{param}",
+				"/v1/users/{param}/posts",
+				"/v1/users/{param}/posts/{id}",
+			},
+			wantValues: map[string]any{
+				"/v1/users/{param}/posts":      30,
+				"/v1/users/{param}/posts/{id}": 30,
+			},
+		},
+		{
+			name: "existing path param is reused",
+			paths: append(siblings(6),
+				pathAndValue{path: "/v1/users/{id}", value: 100},
+				pathAndValue{path: "/v1/users/me", value: 200},
+			),
+			args: args{
+				threshold: 5,
+			},
+			want:         7,
+			wantChildren: []string{"/v1", "/v1/users", "/v1/users/{id}"},
+			wantValues: map[string]any{
+				"/v1/users/{id}": 321,
+				"/v1/users/me":   321,
+			},
+		},
+		{
+			name: "collapsed subtrees are compacted",
+			paths: []pathAndValue{
+				{path: "/v1/a/1", value: 1},
+				{path: "/v1/b/2", value: 2},
+				{path: "/v1/c/3", value: 3},
+			},
+			args: args{
+				threshold: 2,
+			},
+			want:         6,
+			wantChildren: []string{"/v1", "/v1/{param}", "/v1/{param}/{param}"},
+			wantValues: map[string]any{
+				"/v1/{param}/{param}": 6,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			if err := populateDummyPathsAndValue(pt, tt.paths...); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := pt.CompactHighCardinality(tt.args.threshold, sumMerge); got != tt.want {
+				t.Errorf("CompactHighCardinality() = %v, want %v", got, tt.want)
+			}
+
+			gotChildren := pt.GetChildren()
+			sort.Strings(gotChildren)
+			if !reflect.DeepEqual(gotChildren, tt.wantChildren) {
+				t.Errorf("GetChildren() = %v, want %v", gotChildren, tt.wantChildren)
+			}
+
+			for path, want := range tt.wantValues {
+				if got := pt.GetValue(path); !reflect.DeepEqual(got, want) {
+					t.Errorf("GetValue(%s) = %v, want %v", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPathTrie_CompactHighCardinality_fullPaths(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/a/x", value: 1},
+		pathAndValue{path: "/v1/b/x/", value: 2},
+		pathAndValue{path: "/v1/c/{id}", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	pt.CompactHighCardinality(2, nil)
+
+	nodes := map[string]int{}
+	for _, rootNode := range pt.Trie {
+		walkAll(rootNode, func(node *TrieNode) bool {
+			nodes[node.FullPath] = node.PathParamCounter
+			return true
+		})
+	}
+	want := map[string]int{
+		"/v1/{param}/x":    1,
+		"/v1/{param}/x/":   1,
+		"/v1/{param}/{id}": 2,
+	}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("value nodes = %v, want %v", nodes, want)
+	}
+	if got := pt.getNode("/v1/q/{id}").MatchSpecificity(); got != 1.0/3 {
+		t.Errorf("MatchSpecificity() = %v, want %v", got, 1.0/3)
+	}
+}
@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"strings"
+)
+
+// Compress merges every chain of static nodes having a single child and no
+// value into one node, whose Name joins the segments of the chain with the
+// path separator and whose FullPath is the one of the last node of the chain.
+// Path params nodes, value-holding nodes and the root of absolute paths are
+// never merged. Lookups match compressed nodes against as many segments as
+// they join, and inserting a path diverging from a compressed node splits it
+// back. Note that the intermediate paths of a compressed chain are no longer
+// nodes, e.g. for GetChildren, although prefix operations such as DeleteSubtree,
+// GetChildrenOf or GetValueOrInherit still resolve them.
+func (pt *PathTrie) Compress() {
+	pt.compressChildren(pt.Trie)
+	pt.invalidateNodeCount()
+}
+
+func (pt *PathTrie) compressChildren(trie PathToTrieNode) {
+	for key, node := range trie {
+		for pt.isCompressible(node) && len(node.Children) == 1 {
+			var child *TrieNode
+			for _, onlyChild := range node.Children {
+				child = onlyChild
+			}
+			if !pt.isCompressible(child) {
+				break
+			}
+
+			node = &TrieNode{
+				Children:         child.Children,
+				Name:             node.Name + pt.PathSeparator + child.Name,
+				FullPath:         child.FullPath,
+				PathParamCounter: child.PathParamCounter,
+				SegmentCount:     child.SegmentCount,
+				Hits:             child.Hits,
+				LastSeen:         child.LastSeen,
+				ParamNames:       child.ParamNames,
+				observedValues:   child.observedValues,
+			}
+			trie[key] = node
+		}
+
+		pt.compressChildren(node.Children)
+	}
+}
+
+// isCompressible reports whether node can be merged with its parent or child by
+// Compress.
+func (pt *PathTrie) isCompressible(node *TrieNode) bool {
+	return node.Value == nil && node.Name != "" && !pt.isPathParam(node.Name)
+}
+
+// isCompressed reports whether node joins several segments, see Compress. This
+// never happens otherwise, as segments are split on the path separator.
+func (pt *PathTrie) isCompressed(node *TrieNode) bool {
+	return pt.PathSeparator != "" && strings.Contains(node.Name, pt.PathSeparator)
+}
+
+// segmentCount returns the number of segments node joins.
+func (pt *PathTrie) segmentCount(node *TrieNode) int {
+	if !pt.isCompressed(node) {
+		return 1
+	}
+
+	return strings.Count(node.Name, pt.PathSeparator) + 1
+}
+
+// matchNode reports whether node matches the segments starting at idx, and
+// returns the index of the segment following the matched ones. A compressed
+// node matches as many segments as it joins.
+func (pt *PathTrie) matchNode(node *TrieNode, segments []string, idx int) (int, bool) {
+	if !pt.isCompressed(node) {
+		return idx + 1, pt.isNameMatch(node, segments[idx])
+	}
+
+	names := strings.Split(node.Name, pt.PathSeparator)
+	if idx+len(names) > len(segments) {
+		return 0, false
+	}
+	for i, name := range names {
+		if !pt.isSegmentMatch(name, segments[idx+i]) {
+			return 0, false
+		}
+	}
+
+	return idx + len(names), true
+}
+
+// matchNodePrefix is like matchNode, except that a compressed node also matches
+// segments ending within it, e.g. /a/b for a node joining a/b/c, as it then
+// stands for the intermediate node of /a/b, whose descendants are its own.
+func (pt *PathTrie) matchNodePrefix(node *TrieNode, segments []string, idx int) (int, bool) {
+	if next, ok := pt.matchNode(node, segments, idx); ok || !pt.isCompressed(node) {
+		return next, ok
+	}
+
+	names := strings.Split(node.Name, pt.PathSeparator)
+	if idx+len(names) <= len(segments) {
+		return 0, false
+	}
+	for i, segment := range segments[idx:] {
+		if !pt.isSegmentMatch(names[i], segment) {
+			return 0, false
+		}
+	}
+
+	return len(segments), true
+}
+
+// expand splits the compressed node at key in trie back into a chain of nodes,
+// one per segment, and returns the first one.
+func (pt *PathTrie) expand(trie PathToTrieNode, key string) *TrieNode {
+	node := trie[key]
+	names := strings.Split(node.Name, pt.PathSeparator)
+	// The FullPath of the parent, followed by the separator unless node is a root
+	// node.
+	base := strings.TrimSuffix(node.FullPath, node.Name)
+
+	var first *TrieNode
+	for idx, name := range names {
+		isLast := idx == len(names)-1
+		link := &TrieNode{
+			Children:         make(PathToTrieNode),
+			Name:             name,
+			FullPath:         base + strings.Join(names[:idx+1], pt.PathSeparator),
+			PathParamCounter: node.PathParamCounter,
+		}
+		link.SegmentCount = countSegments(strings.Split(link.FullPath, pt.PathSeparator))
+		if isLast {
+			link.Children = node.Children
+			link.Value = node.Value
+			link.Hits = node.Hits
+			link.LastSeen = node.LastSeen
+			link.ParamNames = node.ParamNames
+			link.observedValues = node.observedValues
+		}
+		if first == nil {
+			first = link
+			trie[key] = link
+		} else {
+			trie[pt.nodeKey(name)] = link
+		}
+		trie = link.Children
+	}
+	pt.addNodes(len(names) - 1)
+
+	return first
+}
+
+// expandAll splits back every compressed node of the subtree of trie.
+func (pt *PathTrie) expandAll(trie PathToTrieNode) {
+	for key, node := range trie {
+		if pt.isCompressed(node) {
+			node = pt.expand(trie, key)
+		}
+		pt.expandAll(node.Children)
+	}
+}
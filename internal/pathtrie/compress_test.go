@@ -0,0 +1,314 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func newCompressTestTrie(t *testing.T) PathTrie {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/api/internal/v1/users/{id}", value: 1},
+		pathAndValue{path: "/api/internal/v1/users/{id}/posts/recent", value: 2},
+		pathAndValue{path: "/api/internal/v2", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+	pt.Compress()
+	return pt
+}
+
+func TestPathTrie_Compress(t *testing.T) {
+	pt := newCompressTestTrie(t)
+
+	gotChildren := pt.GetChildren()
+	sort.Strings(gotChildren)
+	wantChildren := []string{
+		"/api/internal",
+		"/api/internal/v1/users",
+		"/api/internal/v1/users/{id}",
+		"/api/internal/v1/users/{id}/posts",
+		"/api/internal/v1/users/{id}/posts/recent",
+		"/api/internal/v2",
+	}
+	if !reflect.DeepEqual(gotChildren, wantChildren) {
+		t.Errorf("GetChildren() = %v, want %v", gotChildren, wantChildren)
+	}
+
+	if got, want := pt.NodeCount(), 7; got != want {
+		t.Errorf("NodeCount() = %v, want %v", got, want)
+	}
+
+	node := pt.Trie[""].Children["api"]
+	if node.Name != "api/internal" || node.FullPath != "/api/internal" {
+		t.Errorf("compressed node = (%v, %v), want (api/internal, /api/internal)", node.Name, node.FullPath)
+	}
+}
+
+func TestPathTrie_Compress_lookups(t *testing.T) {
+	pt := newCompressTestTrie(t)
+
+	tests := []struct {
+		path      string
+		wantPath  string
+		wantValue any
+		wantFound bool
+	}{
+		{path: "/api/internal/v1/users/42", wantPath: "/api/internal/v1/users/{id}", wantValue: 1, wantFound: true},
+		{path: "/api/internal/v1/users/42/posts/recent", wantPath: "/api/internal/v1/users/{id}/posts/recent", wantValue: 2, wantFound: true},
+		{path: "/api/internal/v2", wantPath: "/api/internal/v2", wantValue: 3, wantFound: true},
+		{path: "/api/internal/v1", wantFound: false},
+		{path: "/api/internal/v1/users", wantFound: false},
+		{path: "/api/internal/v1/orders/42", wantFound: false},
+		{path: "/api", wantFound: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			gotPath, gotValue, gotFound := pt.GetPathAndValue(tt.path)
+			if gotPath != tt.wantPath || !reflect.DeepEqual(gotValue, tt.wantValue) || gotFound != tt.wantFound {
+				t.Errorf("GetPathAndValue() = (%v, %v, %v), want (%v, %v, %v)",
+					gotPath, gotValue, gotFound, tt.wantPath, tt.wantValue, tt.wantFound)
+			}
+		})
+	}
+
+	fullPath, _, ok := pt.LongestPrefixMatch("/api/internal/v1/users/42/avatar")
+	if !ok || fullPath != "/api/internal/v1/users/{id}" {
+		t.Errorf("LongestPrefixMatch() = (%v, %v), want (/api/internal/v1/users/{id}, true)", fullPath, ok)
+	}
+	node, remaining, ok := pt.MatchPrefix("/api/internal/v2/a/b")
+	if !ok || node.FullPath != "/api/internal/v2" || !reflect.DeepEqual(remaining, []string{"a", "b"}) {
+		t.Errorf("MatchPrefix() = (%v, %v, %v), want (/api/internal/v2, [a b], true)", node, remaining, ok)
+	}
+}
+
+func TestPathTrie_Compress_insert(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		wantNew       bool
+		wantChildren  []string
+		wantNodeCount int
+	}{
+		{
+			name:    "existing path keeps nodes compressed",
+			path:    "/api/internal/v1/users/{id}",
+			wantNew: false,
+			wantChildren: []string{
+				"/api/internal",
+				"/api/internal/v1/users",
+				"/api/internal/v1/users/{id}",
+				"/api/internal/v1/users/{id}/posts",
+				"/api/internal/v1/users/{id}/posts/recent",
+				"/api/internal/v2",
+			},
+			wantNodeCount: 7,
+		},
+		{
+			name:    "diverging path splits compressed node",
+			path:    "/api/internal/v1/orders",
+			wantNew: true,
+			wantChildren: []string{
+				"/api/internal",
+				"/api/internal/v1",
+				"/api/internal/v1/orders",
+				"/api/internal/v1/users",
+				"/api/internal/v1/users/{id}",
+				"/api/internal/v1/users/{id}/posts",
+				"/api/internal/v1/users/{id}/posts/recent",
+				"/api/internal/v2",
+			},
+			wantNodeCount: 9,
+		},
+		{
+			name:    "path ending in compressed node splits it",
+			path:    "/api",
+			wantNew: true,
+			wantChildren: []string{
+				"/api",
+				"/api/internal",
+				"/api/internal/v1/users",
+				"/api/internal/v1/users/{id}",
+				"/api/internal/v1/users/{id}/posts",
+				"/api/internal/v1/users/{id}/posts/recent",
+				"/api/internal/v2",
+			},
+			wantNodeCount: 8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := newCompressTestTrie(t)
+
+			if got := pt.Insert(tt.path, 4); got != tt.wantNew {
+				t.Errorf("Insert() = %v, want %v", got, tt.wantNew)
+			}
+
+			gotChildren := pt.GetChildren()
+			sort.Strings(gotChildren)
+			if !reflect.DeepEqual(gotChildren, tt.wantChildren) {
+				t.Errorf("GetChildren() = %v, want %v", gotChildren, tt.wantChildren)
+			}
+			if got := pt.NodeCount(); got != tt.wantNodeCount {
+				t.Errorf("NodeCount() = %v, want %v", got, tt.wantNodeCount)
+			}
+			if got := pt.GetValue(tt.path); got != 4 {
+				t.Errorf("GetValue(%s) = %v, want 4", tt.path, got)
+			}
+			if got := pt.GetValue("/api/internal/v1/users/42/posts/recent"); got != 2 {
+				t.Errorf("GetValue(/api/internal/v1/users/42/posts/recent) = %v, want 2", got)
+			}
+		})
+	}
+}
+
+func TestPathTrie_Compress_delete(t *testing.T) {
+	pt := newCompressTestTrie(t)
+
+	if !pt.Delete("/api/internal/v1/users/{id}/posts/recent") {
+		t.Error("Delete() = false, want true")
+	}
+	if got := pt.DeleteSubtree("/api/internal/v1/users"); got != 1 {
+		t.Errorf("DeleteSubtree() = %v, want 1", got)
+	}
+
+	gotChildren := pt.GetChildren()
+	sort.Strings(gotChildren)
+	wantChildren := []string{"/api", "/api/internal", "/api/internal/v2"}
+	if !reflect.DeepEqual(gotChildren, wantChildren) {
+		t.Errorf("GetChildren() = %v, want %v", gotChildren, wantChildren)
+	}
+}
+
+func TestPathTrie_Compress_prefixWithinNode(t *testing.T) {
+	newTrie := func(t *testing.T, paths ...pathAndValue) PathTrie {
+		pt := New()
+		if err := populateDummyPathsAndValue(pt, paths...); err != nil {
+			t.Fatal(err)
+		}
+		pt.Compress()
+		return pt
+	}
+
+	t.Run("DeleteSubtree", func(t *testing.T) {
+		pt := newTrie(t, pathAndValue{path: "/a/b/c/d", value: 1})
+		if got := pt.DeleteSubtree("/a/b"); got != 1 {
+			t.Errorf("DeleteSubtree() = %v, want 1", got)
+		}
+		if got := pt.Size(); got != 0 {
+			t.Errorf("Size() = %v, want 0", got)
+		}
+		if got := pt.NodeCount(); got != 0 {
+			t.Errorf("NodeCount() = %v, want 0", got)
+		}
+	})
+
+	t.Run("GetChildrenOf", func(t *testing.T) {
+		pt := newTrie(t,
+			pathAndValue{path: "/a/b/c/d", value: 1},
+			pathAndValue{path: "/a/b/c/", value: 2},
+		)
+		got := pt.GetChildrenOf("/a/b")
+		sort.Strings(got)
+		if want := []string{"/a/b/c/", "/a/b/c/d"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetChildrenOf() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("GetValueOrInherit", func(t *testing.T) {
+		pt := newTrie(t,
+			pathAndValue{path: "/a", value: 1},
+			pathAndValue{path: "/a/b/c/d", value: 2},
+		)
+		if val, fullPath, ok := pt.GetValueOrInherit("/a/b"); val != 1 || fullPath != "/a" || !ok {
+			t.Errorf("GetValueOrInherit() = %v, %v, %v, want 1, /a, true", val, fullPath, ok)
+		}
+		if _, _, ok := pt.GetValueOrInherit("/a/x"); ok {
+			t.Error("GetValueOrInherit() = true for a diverging path, want false")
+		}
+	})
+}
+
+func TestPathTrie_Compress_caseInsensitive(t *testing.T) {
+	pt := New()
+	pt.CaseInsensitive = true
+	pt.Insert("/API/Internal/v1/users", 1)
+	pt.Compress()
+
+	if got := pt.GetValue("/api/internal/V1/Users"); got != 1 {
+		t.Errorf("GetValue() = %v, want 1", got)
+	}
+	if pt.Insert("/api/INTERNAL/v1/users", 2) {
+		t.Error("Insert() = true, want false")
+	}
+	gotChildren := pt.GetChildren()
+	sort.Strings(gotChildren)
+	wantChildren := []string{"/API/Internal/v1", "/API/Internal/v1/users"}
+	if !reflect.DeepEqual(gotChildren, wantChildren) {
+		t.Errorf("GetChildren() = %v, want %v", gotChildren, wantChildren)
+	}
+}
+
+func TestPathTrie_Compress_insertBatch(t *testing.T) {
+	pt := newCompressTestTrie(t)
+
+	paths := []string{"/api/internal/v1/users/{id}/posts", "/api/internal/v1/users/{id}/likes", "/api/internal/v3"}
+	if err := pt.InsertBatch(paths, []any{5, 6, 7}); err != nil {
+		t.Fatalf("InsertBatch() error = %v", err)
+	}
+	for idx, path := range paths {
+		if got := pt.GetValue(path); got != 5+idx {
+			t.Errorf("GetValue(%s) = %v, want %v", path, got, 5+idx)
+		}
+	}
+}
+
+func BenchmarkPathTrie_Compress(b *testing.B) {
+	var paths []string
+	for service := 0; service < 50; service++ {
+		prefix := fmt.Sprintf("/gateway/api/internal/v1/services/service%d/api/v2", service)
+		for _, resource := range []string{"users", "orders", "invoices", "reports"} {
+			paths = append(paths, prefix+"/"+resource+"/{id}", prefix+"/"+resource+"/{id}/history")
+		}
+	}
+	b.ResetTimer()
+
+	var before, after int
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		pt := New()
+		for idx, path := range paths {
+			pt.Insert(path, idx)
+		}
+		before = pt.NodeCount()
+		b.StartTimer()
+
+		pt.Compress()
+		after = pt.NodeCount()
+	}
+	b.ReportMetric(float64(before), "nodes-before")
+	b.ReportMetric(float64(after), "nodes-after")
+}
+
+func TestPathTrie_Compress_segmentCount(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}/posts", value: 1},
+	); err != nil {
+		t.Fatal(err)
+	}
+	pt.Compress()
+	if got := pt.getNode("/v1/users/42/posts").MatchSpecificity(); got != 0.75 {
+		t.Errorf("MatchSpecificity() = %v, want 0.75", got)
+	}
+
+	pt.Insert("/v1/users/{id}", 2)
+	if got := pt.getNode("/v1/users/42").MatchSpecificity(); got != 2.0/3 {
+		t.Errorf("MatchSpecificity() = %v, want %v", got, 2.0/3)
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"context"
+)
+
+// ctxCheckInterval is the number of nodes visited by a lookup between two
+// checks of its context.
+const ctxCheckInterval = 64
+
+// matchContext carries the cancellation and truncation state of a lookup
+// across the recursive descent of getMatchNodesFunc.
+type matchContext struct {
+	ctx     context.Context
+	visited int
+	err     error
+
+	maxCandidates int
+	candidates    int
+	truncated     bool
+}
+
+// newMatchContext returns the matchContext of a lookup with ctx, which may be
+// nil, or nil if the lookup is neither cancellable nor bounded.
+func (pt *PathTrie) newMatchContext(ctx context.Context) *matchContext {
+	if ctx == nil && pt.MaxCandidates <= 0 {
+		return nil
+	}
+
+	return &matchContext{
+		ctx:           ctx,
+		maxCandidates: pt.MaxCandidates,
+	}
+}
+
+// done reports whether the lookup must stop, either because MaxCandidates
+// nodes were collected or because the context is done, which is checked every
+// ctxCheckInterval visited nodes. A nil matchContext is never done.
+func (mc *matchContext) done() bool {
+	if mc == nil {
+		return false
+	}
+	if mc.maxCandidates > 0 && mc.candidates >= mc.maxCandidates {
+		mc.truncated = true
+		return true
+	}
+	if mc.ctx != nil && mc.err == nil && mc.visited%ctxCheckInterval == 0 {
+		mc.err = mc.ctx.Err()
+	}
+	mc.visited++
+
+	return mc.err != nil
+}
+
+// addCandidate counts a node collected by the lookup.
+func (mc *matchContext) addCandidate() {
+	if mc != nil {
+		mc.candidates++
+	}
+}
+
+// reportTruncation notifies Metrics if the lookup of mc was truncated.
+func (pt *PathTrie) reportTruncation(mc *matchContext) {
+	if mc != nil && mc.truncated && pt.Metrics != nil {
+		pt.Metrics.IncTruncatedMatch()
+	}
+}
+
+// GetValueContext is like GetValue but periodically checks ctx while descending
+// the trie and returns ctx.Err() if it is done before the lookup completes, so
+// that a lookup matching many param or CatchAll branches can't exceed a
+// deadline.
+func (pt *PathTrie) GetValueContext(ctx context.Context, path string) (any, error) {
+	// An empty separator would split path into single characters.
+	if pt.PathSeparator == "" {
+		return nil, nil
+	}
+
+	mc := pt.newMatchContext(ctx)
+	node := pt.getNodeSegmentsContext(pt.splitPath(path), mc)
+	if mc != nil && mc.err != nil {
+		return nil, mc.err
+	}
+	if node == nil {
+		return nil, nil
+	}
+
+	return node.Value, nil
+}
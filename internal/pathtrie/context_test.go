@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPathTrie_GetValueContext(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users", value: 1},
+		pathAndValue{path: "/v1/users/{id}", value: 2},
+		pathAndValue{path: "/v1/{resource}/list", value: 3},
+		pathAndValue{path: "/v1/static/**", value: 4},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"/v1/users", "/v1/users/42", "/v1/users/list", "/v1/static/a/b", "/v2"} {
+		t.Run(path, func(t *testing.T) {
+			got, err := pt.GetValueContext(context.Background(), path)
+			if err != nil {
+				t.Fatalf("GetValueContext() error = %v", err)
+			}
+			if want := pt.GetValue(path); !reflect.DeepEqual(got, want) {
+				t.Errorf("GetValueContext() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_GetValueContext_StrictMatch(t *testing.T) {
+	pt := New()
+	pt.StrictMatch = true
+	pt.Insert("/a/{x}/c", 1)
+	pt.Insert("/a/b/{y}", 2)
+
+	for _, path := range []string{"/a/b/c", "/a/z/c", "/a/b/z"} {
+		t.Run(path, func(t *testing.T) {
+			got, err := pt.GetValueContext(context.Background(), path)
+			if err != nil {
+				t.Fatalf("GetValueContext() error = %v", err)
+			}
+			if want := pt.GetValue(path); !reflect.DeepEqual(got, want) {
+				t.Errorf("GetValueContext() = %v, want %v", got, want)
+			}
+		})
+	}
+	if got, _ := pt.GetValueContext(context.Background(), "/a/b/c"); got != nil {
+		t.Errorf("GetValueContext() = %v for an ambiguous path, want nil", got)
+	}
+}
+
+func TestPathTrie_GetValueContext_emptySeparator(t *testing.T) {
+	pt := New()
+	pt.Insert("/v1", 1)
+	pt.PathSeparator = ""
+	if got, err := pt.GetValueContext(context.Background(), "/v1"); got != nil || err != nil {
+		t.Errorf("GetValueContext() = %v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestPathTrie_GetValueContext_cancelled(t *testing.T) {
+	// Every segment matches every node of a param-heavy trie, so that the lookup
+	// visits all of them.
+	pt := New()
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			pt.Insert(fmt.Sprintf("/{a%d}/{b%d}/{c}/static", i, j), i*10+j)
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{
+			name:    "cancelled",
+			ctx:     cancelled,
+			wantErr: context.Canceled,
+		},
+		{
+			name:    "deadline exceeded",
+			ctx:     expired,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pt.GetValueContext(tt.ctx, "/x/y/z/static")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetValueContext() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != nil {
+				t.Errorf("GetValueContext() = %v, want %v", got, nil)
+			}
+		})
+	}
+}
+
+func TestPathTrie_MaxCandidates(t *testing.T) {
+	type args struct {
+		maxCandidates int
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantMatches   int
+		wantTruncated int
+	}{
+		{
+			name: "unbounded by default",
+			args: args{
+				maxCandidates: 0,
+			},
+			wantMatches:   4,
+			wantTruncated: 0,
+		},
+		{
+			name: "bounded",
+			args: args{
+				maxCandidates: 2,
+			},
+			wantMatches:   2,
+			wantTruncated: 1,
+		},
+		{
+			name: "bound not reached",
+			args: args{
+				maxCandidates: 10,
+			},
+			wantMatches:   4,
+			wantTruncated: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			if err := populateDummyPathsAndValue(pt,
+				pathAndValue{path: "/{a}/x", value: 1},
+				pathAndValue{path: "/{b}/x", value: 2},
+				pathAndValue{path: "/{c}/x", value: 3},
+				pathAndValue{path: "/static/x", value: 4},
+			); err != nil {
+				t.Fatal(err)
+			}
+			metrics := &recordingMetrics{}
+			pt.Metrics = metrics
+			pt.MaxCandidates = tt.args.maxCandidates
+
+			matches := pt.GetMatches("/static/x")
+			if len(matches) != tt.wantMatches {
+				t.Errorf("GetMatches() returned %v nodes, want %v", len(matches), tt.wantMatches)
+			}
+			if metrics.truncated != tt.wantTruncated {
+				t.Errorf("IncTruncatedMatch() calls = %v, want %v", metrics.truncated, tt.wantTruncated)
+			}
+			if got := pt.GetValue("/static/x"); got == nil {
+				t.Errorf("GetValue() = %v, want a value", got)
+			}
+		})
+	}
+}
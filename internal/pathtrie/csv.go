@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// The columns of WriteCSV.
+const (
+	CSVColumnFullPath         = "FullPath"
+	CSVColumnPathParamCounter = "PathParamCounter"
+	CSVColumnHits             = "Hits"
+	CSVColumnValue            = "Value"
+)
+
+var csvColumns = map[string]func(node *TrieNode) string{
+	CSVColumnFullPath: func(node *TrieNode) string {
+		return node.FullPath
+	},
+	CSVColumnPathParamCounter: func(node *TrieNode) string {
+		return strconv.Itoa(node.PathParamCounter)
+	},
+	CSVColumnHits: func(node *TrieNode) string {
+		return strconv.FormatUint(atomic.LoadUint64(&node.Hits), 10)
+	},
+	CSVColumnValue: func(node *TrieNode) string {
+		return fmt.Sprint(node.Value)
+	},
+}
+
+// WriteCSV writes a header row of columns then a row per value-holding node,
+// sorted by FullPath, to w. Columns are chosen among CSVColumnFullPath,
+// CSVColumnPathParamCounter, CSVColumnHits and CSVColumnValue, the latter being
+// formatted with fmt.Sprint, and default to all of them when empty.
+func (pt *PathTrie) WriteCSV(w io.Writer, columns []string) error {
+	if len(columns) == 0 {
+		columns = []string{CSVColumnFullPath, CSVColumnPathParamCounter, CSVColumnHits, CSVColumnValue}
+	}
+	formatters := make([]func(node *TrieNode) string, len(columns))
+	for idx, column := range columns {
+		formatter, ok := csvColumns[column]
+		if !ok {
+			return fmt.Errorf("%w `%s`", ErrUnknownColumn, column)
+		}
+		formatters[idx] = formatter
+	}
+
+	var nodes []*TrieNode
+	for _, rootNode := range pt.Trie {
+		walkAll(rootNode, func(node *TrieNode) bool {
+			nodes = append(nodes, node)
+			return true
+		})
+	}
+	slices.SortFunc(nodes, func(a, b *TrieNode) int {
+		return strings.Compare(a.FullPath, b.FullPath)
+	})
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	row := make([]string, len(columns))
+	for _, node := range nodes {
+		for idx, formatter := range formatters {
+			row[idx] = formatter(node)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
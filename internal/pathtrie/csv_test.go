@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPathTrie_WriteCSV(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users", value: 1},
+		pathAndValue{path: "/v1/users/{id}", value: "a,b"},
+	); err != nil {
+		t.Fatal(err)
+	}
+	pt.Touch("/v1/users/42")
+	pt.Touch("/v1/users/43")
+
+	type args struct {
+		columns []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "all columns by default",
+			args: args{},
+			want: "FullPath,PathParamCounter,Hits,Value\n" +
+				"/v1/users,0,0,1\n" +
+				"/v1/users/{id},1,2,\"a,b\"\n",
+		},
+		{
+			name: "selected columns",
+			args: args{
+				columns: []string{CSVColumnHits, CSVColumnFullPath},
+			},
+			want: "Hits,FullPath\n" +
+				"0,/v1/users\n" +
+				"2,/v1/users/{id}\n",
+		},
+		{
+			name: "unknown column",
+			args: args{
+				columns: []string{CSVColumnFullPath, "Owner"},
+			},
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := pt.WriteCSV(&buf, tt.args.columns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrUnknownColumn) {
+				t.Fatalf("WriteCSV() error = %v, want %v", err, ErrUnknownColumn)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteCSV() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"strings"
+)
+
+// Delete removes the value stored at the exact path and returns true if a value
+// was deleted. Path params are matched by name, not as wildcards, so deleting
+// /v1/foo/{id} never removes /v1/foo/1 nor /v1/foo/{id}/bar.
+// A node that still has children survives with its value cleared, while a leaf
+// node is pruned along with every ancestor left without a value or children.
+func (pt *PathTrie) Delete(path string) bool {
+	segments := pt.splitPath(path)
+
+	parents, node := pt.getExactNode(segments)
+	if node == nil || node.Value == nil {
+		return false
+	}
+	node.Value = nil
+	pt.addNodes(-pt.prune(parents, segments))
+
+	return true
+}
+
+// DeleteSubtree removes the node at prefix along with all of its descendants
+// and returns the number of value-holding nodes removed. The prefix is resolved
+// with the same param-aware matching as lookups, except that the prefix node
+// does not need to hold a value. If several nodes match, the most accurate one
+// is deleted.
+func (pt *PathTrie) DeleteSubtree(prefix string) int {
+	matched := pt.getPrefixNode(prefix)
+	if matched == nil {
+		return 0
+	}
+
+	// Resolve the matched node again by name to find its parents.
+	nodeSegments := strings.Split(matched.FullPath, pt.PathSeparator)
+	parents, node := pt.getExactNode(nodeSegments)
+	if node == nil {
+		return 0
+	}
+
+	removed := countValues(node)
+	delete(parents[len(parents)-1], pt.nodeKey(nodeSegments[len(nodeSegments)-1]))
+	pruned := pt.prune(parents[:len(parents)-1], nodeSegments[:len(nodeSegments)-1])
+	pt.addNodes(-countNodes(node) - pruned)
+
+	return removed
+}
+
+// getExactNode returns the node whose segment names are exactly segments, with
+// no path param substitution, along with the parent map of each node on the way.
+// The compressed nodes on the way are expanded.
+func (pt *PathTrie) getExactNode(segments []string) ([]PathToTrieNode, *TrieNode) {
+	parents := make([]PathToTrieNode, len(segments))
+	trie := pt.Trie
+	var node *TrieNode
+	for idx, segment := range segments {
+		key := pt.nodeKey(segment)
+		var ok bool
+		node, ok = trie[key]
+		if !ok {
+			return nil, nil
+		}
+		if pt.isCompressed(node) {
+			node = pt.expand(trie, key)
+		}
+		parents[idx] = trie
+		trie = node.Children
+	}
+
+	return parents, node
+}
+
+// countValues returns the number of value-holding nodes in the subtree rooted
+// at node, including node itself.
+func countValues(node *TrieNode) int {
+	count := 0
+	if node.Value != nil {
+		count++
+	}
+	for _, child := range node.Children {
+		count += countValues(child)
+	}
+
+	return count
+}
+
+// prune removes the nodes along segments, starting from the deepest one, until
+// it reaches a node that still holds a value or has children. Returns the number
+// of removed nodes.
+func (pt *PathTrie) prune(parents []PathToTrieNode, segments []string) int {
+	removed := 0
+	for idx := len(segments) - 1; idx >= 0; idx-- {
+		key := pt.nodeKey(segments[idx])
+		node := parents[idx][key]
+		if node.Value != nil || len(node.Children) > 0 {
+			break
+		}
+		delete(parents[idx], key)
+		removed++
+	}
+
+	return removed
+}
+
+// Prune removes every subtree holding no value, e.g. one left behind by values
+// cleared in place, and returns the number of removed nodes. Nodes leading to a
+// value-holding node are kept.
+func (pt *PathTrie) Prune() int {
+	removed := pruneEmpty(pt.Trie)
+	pt.addNodes(-removed)
+
+	return removed
+}
+
+// pruneEmpty removes the subtrees of trie holding no value and returns the
+// number of removed nodes.
+func pruneEmpty(trie PathToTrieNode) int {
+	removed := 0
+	for key, node := range trie {
+		removed += pruneEmpty(node.Children)
+		if node.Value == nil && len(node.Children) == 0 {
+			delete(trie, key)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// Reset removes every path in place, keeping the options of the PathTrie, e.g.
+// to rebuild it for each observation window. The nodes and their children maps
+// are kept for reuse by the next insertions, so that a rebuild of similar size
+// allocates little more than the full paths. Hence the TrieNode references
+// obtained before must not be used afterward.
+func (pt *PathTrie) Reset() {
+	if pt.Trie == nil {
+		pt.Trie = make(PathToTrieNode)
+	}
+	for _, node := range pt.Trie {
+		pt.freeNode(node)
+	}
+	clear(pt.Trie)
+	pt.invalidateNodeCount()
+}
+
+// freeNode adds node and its descendants to the nodes reused by newNode.
+func (pt *PathTrie) freeNode(node *TrieNode) {
+	for _, child := range node.Children {
+		pt.freeNode(child)
+	}
+	clear(node.Children)
+	// Drop the references held by the node until it is reused.
+	*node = TrieNode{Children: node.Children}
+	pt.freeNodes = append(pt.freeNodes, node)
+}
@@ -0,0 +1,401 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPathTrie_Delete(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name          string
+		paths         []pathAndValue
+		args          args
+		want          bool
+		wantChildren  []string
+		wantRemaining []string
+	}{
+		{
+			name: "delete leaf prunes childless ancestors",
+			paths: []pathAndValue{
+				{path: "/v1/foo/bar", value: 1},
+				{path: "/v2", value: 2},
+			},
+			args: args{
+				path: "/v1/foo/bar",
+			},
+			want:          true,
+			wantChildren:  []string{"/v2"},
+			wantRemaining: []string{"/v2"},
+		},
+		{
+			name: "delete stops pruning at ancestor holding a value",
+			paths: []pathAndValue{
+				{path: "/v1", value: 1},
+				{path: "/v1/foo/bar", value: 2},
+			},
+			args: args{
+				path: "/v1/foo/bar",
+			},
+			want:          true,
+			wantChildren:  []string{"/v1"},
+			wantRemaining: []string{"/v1"},
+		},
+		{
+			name: "delete node with children only clears its value",
+			paths: []pathAndValue{
+				{path: "/v1/foo/{id}", value: 1},
+				{path: "/v1/foo/{id}/bar", value: 2},
+			},
+			args: args{
+				path: "/v1/foo/{id}",
+			},
+			want:          true,
+			wantChildren:  []string{"/v1", "/v1/foo", "/v1/foo/{id}", "/v1/foo/{id}/bar"},
+			wantRemaining: []string{"/v1/foo/{id}/bar"},
+		},
+		{
+			name: "path param is matched by name",
+			paths: []pathAndValue{
+				{path: "/v1/foo/{id}", value: 1},
+			},
+			args: args{
+				path: "/v1/foo/1",
+			},
+			want:          false,
+			wantChildren:  []string{"/v1", "/v1/foo", "/v1/foo/{id}"},
+			wantRemaining: []string{"/v1/foo/{id}"},
+		},
+		{
+			name: "intermediate node without value is not deleted",
+			paths: []pathAndValue{
+				{path: "/v1/foo/bar", value: 1},
+			},
+			args: args{
+				path: "/v1/foo",
+			},
+			want:          false,
+			wantChildren:  []string{"/v1", "/v1/foo", "/v1/foo/bar"},
+			wantRemaining: []string{"/v1/foo/bar"},
+		},
+		{
+			name: "unknown path",
+			paths: []pathAndValue{
+				{path: "/v1/foo", value: 1},
+			},
+			args: args{
+				path: "/v1/bar",
+			},
+			want:          false,
+			wantChildren:  []string{"/v1", "/v1/foo"},
+			wantRemaining: []string{"/v1/foo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			if err := populateDummyPathsAndValue(pt, tt.paths...); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := pt.Delete(tt.args.path); got != tt.want {
+				t.Errorf("Delete() = %v, want %v", got, tt.want)
+			}
+
+			gotChildren := pt.GetChildren()
+			sort.Strings(gotChildren)
+			if !reflect.DeepEqual(gotChildren, tt.wantChildren) {
+				t.Errorf("GetChildren() = %v, want %v", gotChildren, tt.wantChildren)
+			}
+
+			for _, path := range tt.wantRemaining {
+				if _, _, found := pt.GetPathAndValue(path); !found {
+					t.Errorf("GetPathAndValue(%s) not found after Delete()", path)
+				}
+			}
+			if _, _, found := pt.GetPathAndValue(tt.args.path); found && tt.want {
+				t.Errorf("GetPathAndValue(%s) still found after Delete()", tt.args.path)
+			}
+		})
+	}
+}
+
+func TestPathTrie_Delete_prunesRoot(t *testing.T) {
+	pt := New()
+	pt.Insert("/v1/foo", 1)
+
+	if !pt.Delete("/v1/foo") {
+		t.Fatal("Delete() = false, want true")
+	}
+	if len(pt.Trie) != 0 {
+		t.Errorf("Trie = %v, want empty", marshal(pt.Trie))
+	}
+}
+
+func TestPathTrie_DeleteSubtree(t *testing.T) {
+	paths := []pathAndValue{
+		{path: "/v1/billing", value: 1},
+		{path: "/v1/billing/invoices", value: 2},
+		{path: "/v1/billing/invoices/{id}", value: 3},
+		{path: "/v1/tenants/{tenant}/users", value: 4},
+		{path: "/v1/tenants/{tenant}/users/{id}", value: 5},
+		{path: "/v1/tenants/acme/users", value: 6},
+		{path: "/v2/users", value: 7},
+	}
+	type args struct {
+		prefix string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		want         int
+		wantChildren []string
+	}{
+		{
+			name: "static prefix",
+			args: args{
+				prefix: "/v1/billing",
+			},
+			want: 3,
+			wantChildren: []string{
+				"/v1",
+				"/v1/tenants",
+				"/v1/tenants/acme",
+				"/v1/tenants/acme/users",
+				"/v1/tenants/{tenant}",
+				"/v1/tenants/{tenant}/users",
+				"/v1/tenants/{tenant}/users/{id}",
+				"/v2",
+				"/v2/users",
+			},
+		},
+		{
+			name: "path param prefix",
+			args: args{
+				prefix: "/v1/tenants/{tenant}",
+			},
+			want: 2,
+			wantChildren: []string{
+				"/v1",
+				"/v1/billing",
+				"/v1/billing/invoices",
+				"/v1/billing/invoices/{id}",
+				"/v1/tenants",
+				"/v1/tenants/acme",
+				"/v1/tenants/acme/users",
+				"/v2",
+				"/v2/users",
+			},
+		},
+		{
+			name: "concrete prefix matching a path param node",
+			args: args{
+				prefix: "/v1/tenants/globex",
+			},
+			want: 2,
+			wantChildren: []string{
+				"/v1",
+				"/v1/billing",
+				"/v1/billing/invoices",
+				"/v1/billing/invoices/{id}",
+				"/v1/tenants",
+				"/v1/tenants/acme",
+				"/v1/tenants/acme/users",
+				"/v2",
+				"/v2/users",
+			},
+		},
+		{
+			name: "static prefix is more accurate than path param",
+			args: args{
+				prefix: "/v1/tenants/acme",
+			},
+			want: 1,
+			wantChildren: []string{
+				"/v1",
+				"/v1/billing",
+				"/v1/billing/invoices",
+				"/v1/billing/invoices/{id}",
+				"/v1/tenants",
+				"/v1/tenants/{tenant}",
+				"/v1/tenants/{tenant}/users",
+				"/v1/tenants/{tenant}/users/{id}",
+				"/v2",
+				"/v2/users",
+			},
+		},
+		{
+			name: "prune emptied ancestors",
+			args: args{
+				prefix: "/v2/users",
+			},
+			want: 1,
+			wantChildren: []string{
+				"/v1",
+				"/v1/billing",
+				"/v1/billing/invoices",
+				"/v1/billing/invoices/{id}",
+				"/v1/tenants",
+				"/v1/tenants/acme",
+				"/v1/tenants/acme/users",
+				"/v1/tenants/{tenant}",
+				"/v1/tenants/{tenant}/users",
+				"/v1/tenants/{tenant}/users/{id}",
+			},
+		},
+		{
+			name: "unknown prefix",
+			args: args{
+				prefix: "/v3",
+			},
+			want: 0,
+			wantChildren: []string{
+				"/v1",
+				"/v1/billing",
+				"/v1/billing/invoices",
+				"/v1/billing/invoices/{id}",
+				"/v1/tenants",
+				"/v1/tenants/acme",
+				"/v1/tenants/acme/users",
+				"/v1/tenants/{tenant}",
+				"/v1/tenants/{tenant}/users",
+				"/v1/tenants/{tenant}/users/{id}",
+				"/v2",
+				"/v2/users",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			if err := populateDummyPathsAndValue(pt, paths...); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := pt.DeleteSubtree(tt.args.prefix); got != tt.want {
+				t.Errorf("DeleteSubtree() = %v, want %v", got, tt.want)
+			}
+
+			gotChildren := pt.GetChildren()
+			sort.Strings(gotChildren)
+			if !reflect.DeepEqual(gotChildren, tt.wantChildren) {
+				t.Errorf("GetChildren() = %v, want %v", gotChildren, tt.wantChildren)
+			}
+		})
+	}
+}
+
+func TestPathTrie_Prune(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}/posts", value: 1},
+		pathAndValue{path: "/v1/users/{id}/avatar", value: 2},
+		pathAndValue{path: "/v1/orders/{id}/items/", value: 3},
+		pathAndValue{path: "/v2/health", value: 4},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Clear the leaves in place, which unlike Delete leaves their branches behind.
+	for _, path := range []string{"/v1/users/{id}/avatar", "/v1/orders/{id}/items/", "/v2/health"} {
+		pt.getNode(path).Value = nil
+	}
+	nodeCount := pt.NodeCount()
+
+	// /v1/users/{id}/avatar, /v1/orders/{id}/items/ and its 3 ancestors up to
+	// /v1/orders, and /v2/health with /v2.
+	if got := pt.Prune(); got != 7 {
+		t.Errorf("Prune() = %v, want 7", got)
+	}
+	if got := pt.NodeCount(); got != nodeCount-7 {
+		t.Errorf("NodeCount() = %v, want %v", got, nodeCount-7)
+	}
+	if got := pt.GetValue("/v1/users/42/posts"); got != 1 {
+		t.Errorf("GetValue() = %v, want 1", got)
+	}
+	want := []string{"/v1", "/v1/users", "/v1/users/{id}", "/v1/users/{id}/posts"}
+	if got := pt.GetChildren(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetChildren() = %v, want %v", got, want)
+	}
+
+	if got := pt.Prune(); got != 0 {
+		t.Errorf("Prune() = %v, want 0 on a pruned trie", got)
+	}
+}
+
+func TestPathTrie_Reset(t *testing.T) {
+	pt := NewWithPathSeparator(".")
+	pt.CaseInsensitive = true
+	metrics := &recordingMetrics{}
+	pt.Metrics = metrics
+	pt.Insert(".v1.users.{id}", 1)
+	pt.Insert(".v1.orders", 2)
+	nodes := pt.NodeCount()
+
+	pt.Reset()
+	if got := pt.Size(); got != 0 {
+		t.Errorf("Size() = %v, want 0", got)
+	}
+	if metrics.nodeCount != 0 {
+		t.Errorf("SetNodeCount() = %v, want 0", metrics.nodeCount)
+	}
+	if got := len(pt.freeNodes); got != nodes {
+		t.Errorf("Reset() kept %v nodes for reuse, want %v", got, nodes)
+	}
+
+	// The options survive the reset.
+	pt.Insert(".V1.Users", 3)
+	if got := pt.GetValue(".v1.users"); got != 3 {
+		t.Errorf("GetValue() = %v, want 3", got)
+	}
+	if got := pt.GetValue(".v1.orders"); got != nil {
+		t.Errorf("GetValue() = %v, want nil", got)
+	}
+	if metrics.nodeCount != pt.NodeCount() {
+		t.Errorf("SetNodeCount() = %v, want %v", metrics.nodeCount, pt.NodeCount())
+	}
+	if got, want := len(pt.freeNodes), nodes-pt.NodeCount(); got != want {
+		t.Errorf("Insert() left %v nodes for reuse, want %v", got, want)
+	}
+	if node := pt.getNode(".v1.users"); node.Value != 3 || node.Hits != 0 || len(node.Children) != 0 {
+		t.Errorf("reused node = %+v, want a node holding 3 only", node)
+	}
+
+	var zero PathTrie
+	zero.PathSeparator = "/"
+	zero.Reset()
+	if !zero.Insert("/v1", 1) {
+		t.Error("Insert() = false after Reset() of a zero PathTrie, want true")
+	}
+}
+
+func BenchmarkPathTrie_rebuild(b *testing.B) {
+	paths, vals := newBenchmarkBatch(1_000)
+	rebuild := func(pt *PathTrie) {
+		for idx, path := range paths {
+			pt.Insert(path, vals[idx])
+		}
+	}
+
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			pt := New()
+			rebuild(&pt)
+		}
+	})
+	b.Run("Reset", func(b *testing.B) {
+		b.ReportAllocs()
+		pt := New()
+		for i := 0; i < b.N; i++ {
+			pt.Reset()
+			rebuild(&pt)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// dotRootID is the DOT node ID of the virtual root, parent of the PathTrie root
+// nodes.
+const dotRootID = "root"
+
+// ToDOT writes a Graphviz digraph of the PathTrie to w, e.g. to be rendered with
+// `dot -Tpng`. Each node is labeled with its Name and PathParamCounter, path
+// params nodes are dashed and value-holding nodes are filled. Edges are labeled
+// with the child segment. Nodes are written in a stable order.
+func (pt *PathTrie) ToDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph PathTrie {\n")
+	sb.WriteString("\tnode [shape=box];\n")
+	fmt.Fprintf(&sb, "\t%q [label=%q, shape=point];\n", dotRootID, "")
+	pt.writeDOTChildren(&sb, dotRootID, pt.Trie)
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (pt *PathTrie) writeDOTChildren(sb *strings.Builder, parentID string, trie PathToTrieNode) {
+	for _, key := range sortedKeys(trie) {
+		node := trie[key]
+		// Prefix FullPaths so that a root node named "root" doesn't clash with the
+		// virtual root.
+		id := "n" + node.FullPath
+
+		attrs := []string{fmt.Sprintf("label=%q", fmt.Sprintf("%s (%d)", node.Name, node.PathParamCounter))}
+		var styles []string
+		if pt.isPathParam(node.Name) {
+			styles = append(styles, "dashed")
+		}
+		if node.Value != nil {
+			styles = append(styles, "filled", "bold")
+			attrs = append(attrs, "fillcolor=lightyellow")
+		}
+		if len(styles) > 0 {
+			attrs = append(attrs, fmt.Sprintf("style=%q", strings.Join(styles, ",")))
+		}
+		fmt.Fprintf(sb, "\t%q [%s];\n", id, strings.Join(attrs, ", "))
+		fmt.Fprintf(sb, "\t%q -> %q [label=%q];\n", parentID, id, node.Name)
+
+		pt.writeDOTChildren(sb, id, node.Children)
+	}
+}
+
+// sortedKeys returns the keys of trie sorted, for a stable output.
+func sortedKeys(trie PathToTrieNode) []string {
+	keys := make([]string, 0, len(trie))
+	for key := range trie {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
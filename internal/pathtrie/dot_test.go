@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPathTrie_ToDOT(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users", value: 1},
+		pathAndValue{path: "/v1/users/{id}", value: 2},
+		pathAndValue{path: "/v1/users/{id}/posts", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `digraph PathTrie {
+	node [shape=box];
+	"root" [label="", shape=point];
+	"n" [label=" (0)"];
+	"root" -> "n" [label=""];
+	"n/v1" [label="v1 (0)"];
+	"n" -> "n/v1" [label="v1"];
+	"n/v1/users" [label="users (0)", fillcolor=lightyellow, style="filled,bold"];
+	"n/v1" -> "n/v1/users" [label="users"];
+	"n/v1/users/{id}" [label="{id} (1)", fillcolor=lightyellow, style="dashed,filled,bold"];
+	"n/v1/users" -> "n/v1/users/{id}" [label="{id}"];
+	"n/v1/users/{id}/posts" [label="posts (1)", fillcolor=lightyellow, style="filled,bold"];
+	"n/v1/users/{id}" -> "n/v1/users/{id}/posts" [label="posts"];
+}
+`
+	var buf bytes.Buffer
+	if err := pt.ToDOT(&buf); err != nil {
+		t.Fatalf("ToDOT() error = %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("ToDOT() = %v, want %v", got, want)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestPathTrie_ToDOT_writeError(t *testing.T) {
+	pt := New()
+	pt.Insert("/v1/users", 1)
+
+	if err := pt.ToDOT(failingWriter{}); err == nil {
+		t.Error("ToDOT() error = nil, want error")
+	}
+}
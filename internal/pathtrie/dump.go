@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Dump writes an indented tree of the PathTrie to w, one line per node with
+// children sorted by name. Path params segments are suffixed with `*`,
+// value-holding nodes with `=value`, and empty names, i.e. the root of absolute
+// paths and the end of path markers, are written as `""`.
+func (pt *PathTrie) Dump(w io.Writer) error {
+	var sb strings.Builder
+	pt.dumpChildren(&sb, pt.Trie, 0)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// String returns the tree written by Dump.
+func (pt *PathTrie) String() string {
+	var sb strings.Builder
+	_ = pt.Dump(&sb)
+	return sb.String()
+}
+
+func (pt *PathTrie) dumpChildren(sb *strings.Builder, trie PathToTrieNode, depth int) {
+	for _, node := range sortedByName(trie) {
+		sb.WriteString(strings.Repeat("  ", depth))
+		if node.Name == "" {
+			sb.WriteString(`""`)
+		} else {
+			sb.WriteString(node.Name)
+		}
+		if pt.isPathParam(node.Name) {
+			sb.WriteString("*")
+		}
+		if node.Value != nil {
+			fmt.Fprintf(sb, "=%v", node.Value)
+		}
+		sb.WriteString("\n")
+
+		pt.dumpChildren(sb, node.Children, depth+1)
+	}
+}
+
+// sortedByName returns the nodes of trie sorted by Name, which differs from the
+// key with e.g. CaseInsensitive or UnifyPathParams, then by FullPath.
+func sortedByName(trie PathToTrieNode) []*TrieNode {
+	nodes := make([]*TrieNode, 0, len(trie))
+	for _, node := range trie {
+		nodes = append(nodes, node)
+	}
+	slices.SortFunc(nodes, func(a, b *TrieNode) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.FullPath, b.FullPath))
+	})
+	return nodes
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"testing"
+)
+
+func TestPathTrie_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		paths []pathAndValue
+		want  string
+	}{
+		{
+			name: "empty trie",
+			want: "",
+		},
+		{
+			name: "sorted children",
+			paths: []pathAndValue{
+				{path: "/v1/users/{id}/posts", value: 3},
+				{path: "/v1/users/{id}", value: 2},
+				{path: "/v1/orders/", value: 4},
+				{path: "/v1/users", value: 1},
+				{path: "/v1/accounts/*", value: 5},
+			},
+			want: `""
+  v1
+    accounts
+      **=5
+    orders
+      ""=4
+    users=1
+      {id}*=2
+        posts=3
+`,
+		},
+		{
+			name: "case insensitive children sorted by name",
+			opts: []Option{WithCaseInsensitive()},
+			paths: []pathAndValue{
+				{path: "/alpha", value: 1},
+				{path: "/Zeta", value: 2},
+			},
+			want: `""
+  Zeta=2
+  alpha=1
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New(tt.opts...)
+			if err := populateDummyPathsAndValue(pt, tt.paths...); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := pt.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
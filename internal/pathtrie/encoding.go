@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// encodedPathTrie is the serialized form of a PathTrie.
+type encodedPathTrie struct {
+	PathSeparator string         `json:"pathSeparator"`
+	Trie          PathToTrieNode `json:"trie"`
+}
+
+// MarshalJSON implements json.Marshaler. Values are encoded with encoding/json,
+// so only JSON-encodable values can be persisted, and they are decoded back as
+// their generic JSON representation (e.g. numbers become float64), which is
+// enough to restore the trie structure.
+func (pt PathTrie) MarshalJSON() ([]byte, error) {
+	return json.Marshal(encodedPathTrie{
+		PathSeparator: pt.PathSeparator,
+		Trie:          pt.Trie,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (pt *PathTrie) UnmarshalJSON(data []byte) error {
+	var decoded encodedPathTrie
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	pt.PathSeparator = decoded.PathSeparator
+	pt.Trie = decoded.Trie
+	if pt.Trie == nil {
+		pt.Trie = make(PathToTrieNode)
+	}
+	pt.Trie.initChildren()
+	pt.nodeCountValid = false
+
+	return nil
+}
+
+// initChildren allocates the Children maps that encoders may have dropped, so
+// that the trie can be inserted into after decoding.
+func (trie PathToTrieNode) initChildren() {
+	for _, node := range trie {
+		if node.Children == nil {
+			node.Children = make(PathToTrieNode)
+		}
+		node.Children.initChildren()
+	}
+}
+
+// GobEncode implements gob.GobEncoder. Since Value is an interface, callers
+// must gob.Register every concrete value type stored in the trie before
+// encoding and decoding it.
+func (pt *PathTrie) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(encodedPathTrie{
+		PathSeparator: pt.PathSeparator,
+		Trie:          pt.Trie,
+	}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder.
+func (pt *PathTrie) GobDecode(data []byte) error {
+	var decoded encodedPathTrie
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+
+	pt.PathSeparator = decoded.PathSeparator
+	pt.Trie = decoded.Trie
+	if pt.Trie == nil {
+		pt.Trie = make(PathToTrieNode)
+	}
+	pt.Trie.initChildren()
+	pt.nodeCountValid = false
+
+	return nil
+}
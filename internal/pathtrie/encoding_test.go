@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPathTrie_JSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name          string
+		pathSeparator string
+		paths         []pathAndValue
+	}{
+		{
+			name:          "empty trie",
+			pathSeparator: "/",
+		},
+		{
+			name:          "trie with path params",
+			pathSeparator: "/",
+			paths: []pathAndValue{
+				{path: "/api/items", value: "items"},
+				{path: "/api/{param1}/items", value: 1.5},
+				{path: "/api/{param1}/{param2}", value: map[string]any{"GET": true}},
+			},
+		},
+		{
+			name:          "custom path separator",
+			pathSeparator: ".",
+			paths: []pathAndValue{
+				{path: "api.{param1}.items", value: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := NewWithPathSeparator(tt.pathSeparator)
+			for _, pathNdValue := range tt.paths {
+				pt.Insert(pathNdValue.path, pathNdValue.value)
+			}
+
+			data, err := json.Marshal(pt)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			var got PathTrie
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+
+			if got.PathSeparator != pt.PathSeparator {
+				t.Errorf("PathSeparator = %v, want %v", got.PathSeparator, pt.PathSeparator)
+			}
+			if !reflect.DeepEqual(got.Trie, pt.Trie) {
+				t.Errorf("Trie = %v, want %v", marshal(got.Trie), marshal(pt.Trie))
+			}
+			gotChildren, wantChildren := got.GetChildren(), pt.GetChildren()
+			sort.Strings(gotChildren)
+			sort.Strings(wantChildren)
+			if !reflect.DeepEqual(gotChildren, wantChildren) {
+				t.Errorf("GetChildren() = %v, want %v", gotChildren, wantChildren)
+			}
+			for _, pathNdValue := range tt.paths {
+				if gotValue := got.GetValue(pathNdValue.path); !reflect.DeepEqual(gotValue, pathNdValue.value) {
+					t.Errorf("GetValue(%s) = %v, want %v", pathNdValue.path, gotValue, pathNdValue.value)
+				}
+			}
+
+			// The decoded trie must remain usable.
+			if !got.Insert("/api/new", 1) {
+				t.Errorf("Insert() after UnmarshalJSON() = false, want true")
+			}
+		})
+	}
+}
+
+type endpointGroup struct {
+	ID    string
+	Calls int
+}
+
+func TestPathTrie_GobRoundTrip(t *testing.T) {
+	gob.Register(endpointGroup{})
+
+	tests := []struct {
+		name          string
+		pathSeparator string
+		paths         []pathAndValue
+	}{
+		{
+			name:          "empty trie",
+			pathSeparator: "/",
+		},
+		{
+			name:          "trie with path params",
+			pathSeparator: "/",
+			paths: []pathAndValue{
+				{path: "/api/items", value: "items"},
+				{path: "/api/{param1}/items", value: 1},
+				{path: "/api/{param1}/{param2}", value: endpointGroup{ID: "group", Calls: 2}},
+			},
+		},
+		{
+			name:          "custom path separator",
+			pathSeparator: ".",
+			paths: []pathAndValue{
+				{path: "api.{param1}.items", value: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := NewWithPathSeparator(tt.pathSeparator)
+			for _, pathNdValue := range tt.paths {
+				pt.Insert(pathNdValue.path, pathNdValue.value)
+			}
+
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(&pt); err != nil {
+				t.Fatalf("GobEncode() error = %v", err)
+			}
+			var got PathTrie
+			if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+				t.Fatalf("GobDecode() error = %v", err)
+			}
+
+			if got.PathSeparator != pt.PathSeparator {
+				t.Errorf("PathSeparator = %v, want %v", got.PathSeparator, pt.PathSeparator)
+			}
+			if !reflect.DeepEqual(got.Trie, pt.Trie) {
+				t.Errorf("Trie = %v, want %v", marshal(got.Trie), marshal(pt.Trie))
+			}
+
+			// The decoded trie must remain usable.
+			if !got.Insert("/api/new", 1) {
+				t.Errorf("Insert() after GobDecode() = false, want true")
+			}
+		})
+	}
+}
+
+func newBenchmarkTrie(paths int) PathTrie {
+	pt := New()
+	for i := 0; i < paths; i++ {
+		pt.Insert(fmt.Sprintf("/api/v%d/service%d/{id}/items/%d", i%3, i%100, i), i)
+	}
+	return pt
+}
+
+func BenchmarkPathTrie_GobEncode(b *testing.B) {
+	pt := newBenchmarkTrie(100_000)
+	b.ResetTimer()
+
+	var size int
+	for i := 0; i < b.N; i++ {
+		data, err := pt.GobEncode()
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = len(data)
+	}
+	b.ReportMetric(float64(size), "bytes")
+}
+
+func BenchmarkPathTrie_MarshalJSON(b *testing.B) {
+	pt := newBenchmarkTrie(100_000)
+	b.ResetTimer()
+
+	var size int
+	for i := 0; i < b.N; i++ {
+		data, err := pt.MarshalJSON()
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = len(data)
+	}
+	b.ReportMetric(float64(size), "bytes")
+}
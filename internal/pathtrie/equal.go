@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+)
+
+// Equal reports whether pt and other have the same separator and the same nodes,
+// comparing their Name, FullPath, PathParamCounter and Value, the latter with
+// valueEqual, or reflect.DeepEqual if nil. valueEqual is only called when both
+// values are set. Hits and ParamNames are ignored. As the comparison is
+// structural, a compressed trie doesn't equal its uncompressed counterpart.
+func (pt *PathTrie) Equal(other *PathTrie, valueEqual func(a, b any) bool) bool {
+	if other == nil || pt.PathSeparator != other.PathSeparator {
+		return false
+	}
+	if valueEqual == nil {
+		valueEqual = reflect.DeepEqual
+	}
+
+	return pt.Trie.equal(other.Trie, valueEqual)
+}
+
+func (trie PathToTrieNode) equal(other PathToTrieNode, valueEqual func(a, b any) bool) bool {
+	if len(trie) != len(other) {
+		return false
+	}
+
+	for key, node := range trie {
+		otherNode, ok := other[key]
+		if !ok || !node.equal(otherNode, valueEqual) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (node *TrieNode) equal(other *TrieNode, valueEqual func(a, b any) bool) bool {
+	if node.Name != other.Name || node.FullPath != other.FullPath || node.PathParamCounter != other.PathParamCounter {
+		return false
+	}
+	if (node.Value == nil) != (other.Value == nil) {
+		return false
+	}
+	if node.Value != nil && !valueEqual(node.Value, other.Value) {
+		return false
+	}
+
+	return node.Children.equal(other.Children, valueEqual)
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPathTrie_Equal(t *testing.T) {
+	newTrie := func(separator string, paths ...pathAndValue) *PathTrie {
+		pt := NewWithPathSeparator(separator)
+		if err := populateDummyPathsAndValue(pt, paths...); err != nil {
+			t.Fatal(err)
+		}
+		return &pt
+	}
+	golden := newTrie("/",
+		pathAndValue{path: "/v1/users/{id}", value: []string{"GET"}},
+		pathAndValue{path: "/v1/users/", value: []string{"POST"}},
+	)
+
+	// sameLength is a loose comparator, matching any values of the same length.
+	sameLength := func(a, b any) bool {
+		return len(a.([]string)) == len(b.([]string))
+	}
+
+	type args struct {
+		other      *PathTrie
+		valueEqual func(a, b any) bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "same paths inserted in another order",
+			args: args{
+				other: newTrie("/",
+					pathAndValue{path: "/v1/users/", value: []string{"POST"}},
+					pathAndValue{path: "/v1/users/{id}", value: []string{"GET"}},
+				),
+			},
+			want: true,
+		},
+		{
+			name: "different value",
+			args: args{
+				other: newTrie("/",
+					pathAndValue{path: "/v1/users/{id}", value: []string{"PUT"}},
+					pathAndValue{path: "/v1/users/", value: []string{"POST"}},
+				),
+			},
+			want: false,
+		},
+		{
+			name: "different value matching the comparator",
+			args: args{
+				other: newTrie("/",
+					pathAndValue{path: "/v1/users/{id}", value: []string{"PUT"}},
+					pathAndValue{path: "/v1/users/", value: []string{"POST"}},
+				),
+				valueEqual: sameLength,
+			},
+			want: true,
+		},
+		{
+			name: "missing value",
+			args: args{
+				other: newTrie("/",
+					pathAndValue{path: "/v1/users/{id}", value: []string{"GET"}},
+				),
+				valueEqual: sameLength,
+			},
+			want: false,
+		},
+		{
+			name: "differently named param",
+			args: args{
+				other: newTrie("/",
+					pathAndValue{path: "/v1/users/{userId}", value: []string{"GET"}},
+					pathAndValue{path: "/v1/users/", value: []string{"POST"}},
+				),
+			},
+			want: false,
+		},
+		{
+			name: "different separator",
+			args: args{
+				other: newTrie(".",
+					pathAndValue{path: "/v1/users/{id}", value: []string{"GET"}},
+					pathAndValue{path: "/v1/users/", value: []string{"POST"}},
+				),
+			},
+			want: false,
+		},
+		{
+			name: "nil trie",
+			args: args{
+				other: nil,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := golden.Equal(tt.args.other, tt.args.valueEqual); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A round trip through JSON preserves the trie, though values are decoded as
+	// []any.
+	data, err := golden.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := New()
+	if err := decoded.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(golden, func(a, b any) bool {
+		return fmt.Sprint(a) == fmt.Sprint(b)
+	}) {
+		t.Errorf("Equal() = false after a JSON round trip, want true")
+	}
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"errors"
+)
+
+// The errors returned by the PathTrie APIs are wrapped with the offending path
+// or value, so that callers can check them with errors.Is.
+var (
+	// ErrMethodNotAllowed is returned by MatchOperation when the path matches but
+	// holds no operation for the method.
+	ErrMethodNotAllowed = errors.New("method not allowed")
+
+	// ErrPathNotFound is returned by GetValueStrict when no node matches the
+	// path.
+	ErrPathNotFound = errors.New("path not found")
+
+	// ErrAmbiguousMatch is returned by GetValueStrict when several nodes match
+	// the path equally accurately.
+	ErrAmbiguousMatch = errors.New("ambiguous match")
+
+	// ErrMaxParamsExceeded is returned by TryInsertMerge when the path has more
+	// path params than MaxPathParams.
+	ErrMaxParamsExceeded = errors.New("max path params exceeded")
+
+	// ErrMaxDepthExceeded is returned by TryInsertMerge when the path has more
+	// segments than MaxDepth.
+	ErrMaxDepthExceeded = errors.New("max depth exceeded")
+
+	// ErrEmptySegment is returned by TryInsertMerge when the path has an empty
+	// segment and RejectEmptySegments is set.
+	ErrEmptySegment = errors.New("empty segment")
+
+	// ErrCatchAllNotLast is returned by TryInsertMerge when a CatchAll segment is
+	// followed by other segments, which it would make unreachable.
+	ErrCatchAllNotLast = errors.New("catch-all segment not last")
+
+	// ErrPathFiltered is returned by TryInsertMerge when the path is skipped
+	// because of IncludePrefixes or ExcludePrefixes.
+	ErrPathFiltered = errors.New("path filtered")
+
+	// ErrEmptySeparator is returned by NewWithPathSeparatorChecked, and by
+	// TryInsertMerge and GetValueStrict, when the path separator is empty.
+	ErrEmptySeparator = errors.New("empty path separator")
+
+	// ErrSeparatorMismatch is returned by MergeTrie when the tries don't use the
+	// same path separator.
+	ErrSeparatorMismatch = errors.New("path separator mismatch")
+
+	// ErrLengthMismatch is returned by InsertBatch when there aren't as many
+	// values as paths.
+	ErrLengthMismatch = errors.New("length mismatch")
+
+	// ErrUnknownColumn is returned by WriteCSV for a column that isn't one of the
+	// CSVColumn constants.
+	ErrUnknownColumn = errors.New("unknown CSV column")
+)
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+// isFiltered reports whether the path of segments is skipped by IncludePrefixes
+// or ExcludePrefixes.
+func (pt *PathTrie) isFiltered(segments []string) bool {
+	if len(pt.IncludePrefixes) > 0 && !pt.hasAnyPrefix(segments, pt.IncludePrefixes) {
+		return true
+	}
+
+	return pt.hasAnyPrefix(segments, pt.ExcludePrefixes)
+}
+
+// hasAnyPrefix reports whether the path of segments starts with one of
+// prefixes.
+func (pt *PathTrie) hasAnyPrefix(segments []string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if pt.hasPrefix(segments, pt.splitPath(prefix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasPrefix reports whether the path of segments starts with the segments of a
+// prefix, regardless of a trailing separator of the prefix. The path params of
+// the prefix match any segment, and a CatchAll one the remaining segments.
+func (pt *PathTrie) hasPrefix(segments, prefix []string) bool {
+	if last := len(prefix) - 1; last > 0 && prefix[last] == "" {
+		prefix = prefix[:last]
+	}
+	if len(prefix) > len(segments) {
+		return false
+	}
+
+	for idx, name := range prefix {
+		if name == CatchAll {
+			return true
+		}
+		if !pt.isSegmentMatch(name, segments[idx]) {
+			return false
+		}
+	}
+
+	return true
+}
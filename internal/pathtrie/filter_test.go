@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPathTrie_TryInsertMerge_prefixes(t *testing.T) {
+	overwrite := func(existing, newV *any) {
+		*existing = *newV
+	}
+
+	type args struct {
+		include []string
+		exclude []string
+		path    string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantFiltered bool
+	}{
+		{
+			name: "no prefixes",
+			args: args{
+				path: "/healthz",
+			},
+			wantFiltered: false,
+		},
+		{
+			name: "excluded",
+			args: args{
+				exclude: []string{"/healthz", "/metrics"},
+				path:    "/metrics",
+			},
+			wantFiltered: true,
+		},
+		{
+			name: "excluded descendant",
+			args: args{
+				exclude: []string{"/healthz/"},
+				path:    "/healthz/live",
+			},
+			wantFiltered: true,
+		},
+		{
+			name: "prefixes match whole segments",
+			args: args{
+				exclude: []string{"/healthz"},
+				path:    "/healthzz",
+			},
+			wantFiltered: false,
+		},
+		{
+			name: "excluded by a param prefix",
+			args: args{
+				exclude: []string{"/v1/{tenant}/internal"},
+				path:    "/v1/acme/internal/jobs",
+			},
+			wantFiltered: true,
+		},
+		{
+			name: "included",
+			args: args{
+				include: []string{"/v1", "/v2"},
+				path:    "/v2/users",
+			},
+			wantFiltered: false,
+		},
+		{
+			name: "not included",
+			args: args{
+				include: []string{"/v1", "/v2"},
+				path:    "/v3/users",
+			},
+			wantFiltered: true,
+		},
+		{
+			name: "included but excluded",
+			args: args{
+				include: []string{"/v1"},
+				exclude: []string{"/v1/metrics"},
+				path:    "/v1/metrics",
+			},
+			wantFiltered: true,
+		},
+		{
+			name: "included by a catch-all prefix",
+			args: args{
+				include: []string{"/v1/**"},
+				path:    "/v1/users/42",
+			},
+			wantFiltered: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			pt.IncludePrefixes = tt.args.include
+			pt.ExcludePrefixes = tt.args.exclude
+
+			isNew, err := pt.TryInsertMerge(tt.args.path, 1, overwrite)
+			if got := errors.Is(err, ErrPathFiltered); got != tt.wantFiltered {
+				t.Errorf("TryInsertMerge() error = %v, want filtered %v", err, tt.wantFiltered)
+			}
+			if isNew == tt.wantFiltered {
+				t.Errorf("TryInsertMerge() = %v, want %v", isNew, !tt.wantFiltered)
+			}
+			if got := pt.GetValue(tt.args.path) == nil; got != tt.wantFiltered {
+				t.Errorf("GetValue() = %v, want filtered %v", pt.GetValue(tt.args.path), tt.wantFiltered)
+			}
+		})
+	}
+}
+
+func TestPathTrie_prefixesWithOptions(t *testing.T) {
+	pt := NewWithPathSeparator(".")
+	pt.CaseInsensitive = true
+	pt.ExcludePrefixes = []string{".Health"}
+
+	if pt.Insert(".health.live", 1) {
+		t.Error("Insert() = true for an excluded path, want false")
+	}
+	if !pt.Insert(".v1.users", 2) {
+		t.Error("Insert() = false, want true")
+	}
+	want := []string{".v1", ".v1.users"}
+	if got := pt.GetChildren(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetChildren() = %v, want %v", got, want)
+	}
+}
+
+func TestPathTrie_InsertBatch_prefixes(t *testing.T) {
+	pt := New()
+	pt.ExcludePrefixes = []string{"/metrics"}
+	if err := pt.InsertBatch([]string{"/v1/users", "/metrics", "/v1/orders"}, []any{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/v1/orders", "/v1/users"}
+	if got := pt.PathsWithValue(func(any) bool { return true }); !reflect.DeepEqual(got, want) {
+		t.Errorf("PathsWithValue() = %v, want %v", got, want)
+	}
+}
+
+func TestPathTrie_InsertSegments_prefixes(t *testing.T) {
+	pt := New()
+	pt.ExcludePrefixes = []string{"/healthz"}
+
+	if pt.InsertSegments([]string{"", "healthz"}, 1) {
+		t.Error("InsertSegments() = true for an excluded path, want false")
+	}
+	if !pt.InsertSegments([]string{"", "v1", "users"}, 2) {
+		t.Error("InsertSegments() = false, want true")
+	}
+	if got := pt.GetValue("/healthz"); got != nil {
+		t.Errorf("GetValue() = %v, want nil", got)
+	}
+}
+
+func TestPathTrie_InsertOperation_prefixes(t *testing.T) {
+	pt := New()
+	pt.IncludePrefixes = []string{"/v1"}
+	pt.ExcludePrefixes = []string{"/v1/metrics"}
+
+	for _, path := range []string{"/healthz", "/v1/metrics"} {
+		if pt.InsertOperation("GET", path, 1) {
+			t.Errorf("InsertOperation(%s) = true for a filtered path, want false", path)
+		}
+		if got, ok := pt.GetOperation("GET", path); ok {
+			t.Errorf("GetOperation(%s) = %v, want none", path, got)
+		}
+	}
+	if !pt.InsertOperation("GET", "/v1/users", 2) {
+		t.Error("InsertOperation() = false, want true")
+	}
+	if got, _ := pt.GetOperation("GET", "/v1/users"); got != 2 {
+		t.Errorf("GetOperation() = %v, want 2", got)
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InsertError is returned by TryInsertMerge when a path is rejected by the
+// insertion guards. It wraps every reason for the rejection, so that
+// errors.Is(err, ErrMaxDepthExceeded) reports whether the depth was exceeded.
+type InsertError struct {
+	Path    string
+	Reasons []error
+}
+
+func (e *InsertError) Error() string {
+	reasons := make([]string, len(e.Reasons))
+	for idx, reason := range e.Reasons {
+		reasons[idx] = reason.Error()
+	}
+
+	return fmt.Sprintf("cannot insert path `%s`: %s", e.Path, strings.Join(reasons, ", "))
+}
+
+func (e *InsertError) Unwrap() []error {
+	return e.Reasons
+}
+
+// validatePath returns an InsertError if the segments of path are rejected by
+// the insertion guards, before anything is inserted.
+func (pt *PathTrie) validatePath(path string, segments []string) error {
+	// Nothing else is meaningful if the segments were split on nothing.
+	if pt.PathSeparator == "" {
+		return &InsertError{Path: path, Reasons: []error{ErrEmptySeparator}}
+	}
+
+	var reasons []error
+
+	// The segments after a CatchAll one are unreachable, as it consumes them.
+	for idx, segment := range segments[:max(len(segments)-1, 0)] {
+		if segment == CatchAll {
+			reasons = append(reasons, fmt.Errorf("%w at index %d", ErrCatchAllNotLast, idx))
+			break
+		}
+	}
+
+	if pt.MaxDepth > 0 {
+		depth := len(segments)
+		if depth > 1 && segments[0] == "" {
+			depth--
+		}
+		if depth > pt.MaxDepth {
+			reasons = append(reasons, fmt.Errorf("%w: %d segments, max %d", ErrMaxDepthExceeded, depth, pt.MaxDepth))
+		}
+	}
+
+	if pt.MaxPathParams > 0 {
+		if count := pt.countPathParam(segments); count > pt.MaxPathParams {
+			reasons = append(reasons, fmt.Errorf("%w: %d path params, max %d", ErrMaxParamsExceeded, count, pt.MaxPathParams))
+		}
+	}
+
+	if pt.RejectEmptySegments {
+		// The root of absolute paths and the trailing end of path marker are
+		// allowed.
+		for idx := 1; idx < len(segments)-1; idx++ {
+			if segments[idx] == "" {
+				reasons = append(reasons, fmt.Errorf("%w at index %d", ErrEmptySegment, idx))
+				break
+			}
+		}
+	}
+
+	if len(reasons) > 0 {
+		return &InsertError{Path: path, Reasons: reasons}
+	}
+
+	return nil
+}
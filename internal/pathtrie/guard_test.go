@@ -0,0 +1,303 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPathTrie_TryInsertMerge_MaxPathParams(t *testing.T) {
+	overwrite := func(existing, newV *any) {
+		*existing = *newV
+	}
+
+	type args struct {
+		maxPathParams int
+		path          string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "unlimited by default",
+			args: args{
+				maxPathParams: 0,
+				path:          "/{a}/{b}/{c}/{d}",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "at the limit",
+			args: args{
+				maxPathParams: 2,
+				path:          "/v1/{tenant}/users/{id}",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "above the limit",
+			args: args{
+				maxPathParams: 2,
+				path:          "/{a}/{b}/{c}",
+			},
+			wantErr: ErrMaxParamsExceeded,
+		},
+		{
+			name: "wildcards count as path params",
+			args: args{
+				maxPathParams: 1,
+				path:          "/v1/*/**",
+			},
+			wantErr: ErrMaxParamsExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			pt.MaxPathParams = tt.args.maxPathParams
+
+			isNewPath, err := pt.TryInsertMerge(tt.args.path, 1, overwrite)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TryInsertMerge() error = %v, want %v", err, tt.wantErr)
+			}
+			if isNewPath != (tt.wantErr == nil) {
+				t.Errorf("TryInsertMerge() isNewPath = %v, want %v", isNewPath, tt.wantErr == nil)
+			}
+			if tt.wantErr != nil && pt.NodeCount() != 0 {
+				t.Errorf("NodeCount() = %v, want 0 after a rejected insert", pt.NodeCount())
+			}
+		})
+	}
+}
+
+func TestPathTrie_InsertMerge_MaxPathParams(t *testing.T) {
+	pt := New()
+	pt.MaxPathParams = 1
+
+	if got := pt.Insert("/{a}/{b}", 1); got {
+		t.Errorf("Insert() = %v, want %v", got, false)
+	}
+	if got := pt.GetValue("/x/y"); got != nil {
+		t.Errorf("GetValue() = %v, want %v", got, nil)
+	}
+
+	err := pt.InsertBatch([]string{"/v1/{id}", "/{a}/{b}"}, []any{1, 2})
+	if !errors.Is(err, ErrMaxParamsExceeded) {
+		t.Errorf("InsertBatch() error = %v, want %v", err, ErrMaxParamsExceeded)
+	}
+	if got := pt.Size(); got != 0 {
+		t.Errorf("Size() = %v, want 0 after a rejected batch", got)
+	}
+}
+
+func TestPathTrie_TryInsertMerge_MaxDepth(t *testing.T) {
+	overwrite := func(existing, newV *any) {
+		*existing = *newV
+	}
+
+	type args struct {
+		maxDepth int
+		path     string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "unlimited by default",
+			args: args{
+				maxDepth: 0,
+				path:     "/" + strings.Repeat("a/", 1000) + "b",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "at the limit",
+			args: args{
+				maxDepth: 3,
+				path:     "/v1/users/{id}",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "above the limit",
+			args: args{
+				maxDepth: 3,
+				path:     "/v1/users/{id}/avatar",
+			},
+			wantErr: ErrMaxDepthExceeded,
+		},
+		{
+			name: "empty segments count",
+			args: args{
+				maxDepth: 3,
+				path:     "/v1////users",
+			},
+			wantErr: ErrMaxDepthExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			pt.MaxDepth = tt.args.maxDepth
+			pt.Insert("/v1/users", 1)
+			nodeCount := pt.NodeCount()
+
+			_, err := pt.TryInsertMerge(tt.args.path, 2, overwrite)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TryInsertMerge() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && pt.NodeCount() != nodeCount {
+				t.Errorf("NodeCount() = %v, want %v after a rejected insert", pt.NodeCount(), nodeCount)
+			}
+		})
+	}
+}
+
+func TestPathTrie_TryInsertMerge_InsertError(t *testing.T) {
+	pt := New()
+	pt.MaxDepth = 3
+	pt.MaxPathParams = 1
+	pt.RejectEmptySegments = true
+	pt.Insert("/v1/users", 1)
+	nodeCount := pt.NodeCount()
+
+	_, err := pt.TryInsertMerge("/v1//{tenant}/users/{id}", 2, func(existing, newV *any) {
+		*existing = *newV
+	})
+
+	var insertErr *InsertError
+	if !errors.As(err, &insertErr) {
+		t.Fatalf("TryInsertMerge() error = %v, want an *InsertError", err)
+	}
+	if insertErr.Path != "/v1//{tenant}/users/{id}" {
+		t.Errorf("InsertError.Path = %v, want %v", insertErr.Path, "/v1//{tenant}/users/{id}")
+	}
+	for _, wantErr := range []error{ErrMaxDepthExceeded, ErrMaxParamsExceeded, ErrEmptySegment} {
+		if !errors.Is(err, wantErr) {
+			t.Errorf("TryInsertMerge() error = %v, want it to wrap %v", err, wantErr)
+		}
+	}
+	want := "cannot insert path `/v1//{tenant}/users/{id}`: max depth exceeded: 5 segments, max 3, " +
+		"max path params exceeded: 2 path params, max 1, empty segment at index 2"
+	if err.Error() != want {
+		t.Errorf("TryInsertMerge() error = %v, want %v", err, want)
+	}
+	if pt.NodeCount() != nodeCount {
+		t.Errorf("NodeCount() = %v, want %v after a rejected insert", pt.NodeCount(), nodeCount)
+	}
+}
+
+func TestPathTrie_TryInsertMerge_RejectEmptySegments(t *testing.T) {
+	overwrite := func(existing, newV *any) {
+		*existing = *newV
+	}
+
+	type args struct {
+		collapseEmptySegments bool
+		path                  string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "root and trailing separator are allowed",
+			args: args{
+				path: "/v1/users/",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "empty inner segment",
+			args: args{
+				path: "/v1//users",
+			},
+			wantErr: ErrEmptySegment,
+		},
+		{
+			name: "collapsed empty segments",
+			args: args{
+				collapseEmptySegments: true,
+				path:                  "/v1//users",
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			pt.RejectEmptySegments = true
+			pt.CollapseEmptySegments = tt.args.collapseEmptySegments
+
+			if _, err := pt.TryInsertMerge(tt.args.path, 1, overwrite); !errors.Is(err, tt.wantErr) {
+				t.Errorf("TryInsertMerge() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPathTrie_TryInsertMerge_EmptySeparator(t *testing.T) {
+	pt := New()
+	pt.Insert("/v1/users", 1)
+	// E.g. a separator reset by a zero-value configuration.
+	pt.PathSeparator = ""
+
+	if _, err := pt.TryInsertMerge("/v1/orders", 2, nil); !errors.Is(err, ErrEmptySeparator) {
+		t.Errorf("TryInsertMerge() error = %v, want %v", err, ErrEmptySeparator)
+	}
+	if got := pt.NodeCount(); got != 3 {
+		t.Errorf("NodeCount() = %v, want 3", got)
+	}
+	if got := pt.GetValue("/v1/users"); got != nil {
+		t.Errorf("GetValue() = %v, want nil", got)
+	}
+}
+
+func TestPathTrie_TryInsertMerge_CatchAllNotLast(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "catch-all last",
+			args: args{
+				path: "/files/**",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "catch-all followed by segments",
+			args: args{
+				path: "/files/**/raw",
+			},
+			wantErr: ErrCatchAllNotLast,
+		},
+		{
+			name: "catch-all followed by a trailing separator",
+			args: args{
+				path: "/files/**/",
+			},
+			wantErr: ErrCatchAllNotLast,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			if _, err := pt.TryInsertMerge(tt.args.path, 1, nil); !errors.Is(err, tt.wantErr) {
+				t.Errorf("TryInsertMerge() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
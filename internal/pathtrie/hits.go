@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Touch resolves path like GetValue and increments the Hits counter of the
+// matched node, if any, setting its LastSeen time. They are updated atomically,
+// but resolving the path is not safe while the PathTrie is mutated: use
+// SafePathTrie.Touch to call it concurrently.
+func (pt *PathTrie) Touch(path string) {
+	node := pt.getNode(path)
+	if node == nil {
+		return
+	}
+
+	atomic.AddUint64(&node.Hits, 1)
+	atomic.StoreInt64(&node.LastSeen, time.Now().UnixNano())
+}
+
+// LastSeenTime returns the LastSeen time of the node, or the zero time if it was
+// never touched.
+func (node *TrieNode) LastSeenTime() time.Time {
+	lastSeen := atomic.LoadInt64(&node.LastSeen)
+	if lastSeen == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, lastSeen)
+}
+
+// PathsNotSeenSince returns the sorted full paths of the value-holding nodes not
+// touched since t, including the never touched ones, e.g. to find the endpoints
+// not called in the last 30 days. See Touch.
+func (pt *PathTrie) PathsNotSeenSince(t time.Time) []string {
+	var paths []string
+	for _, rootNode := range pt.Trie {
+		walkAll(rootNode, func(node *TrieNode) bool {
+			if lastSeen := node.LastSeenTime(); lastSeen.IsZero() || lastSeen.Before(t) {
+				paths = append(paths, node.FullPath)
+			}
+			return true
+		})
+	}
+
+	slices.Sort(paths)
+	return paths
+}
+
+// TopN returns the n value-holding nodes with the most Hits, from the most to
+// the least hit, ties being sorted by FullPath. All of them are returned if
+// there are less than n, and none if n isn't positive.
+func (pt *PathTrie) TopN(n int) []*TrieNode {
+	if n <= 0 {
+		return nil
+	}
+
+	var nodes []*TrieNode
+	for _, rootNode := range pt.Trie {
+		walkAll(rootNode, func(node *TrieNode) bool {
+			nodes = append(nodes, node)
+			return true
+		})
+	}
+
+	slices.SortFunc(nodes, func(a, b *TrieNode) int {
+		aHits, bHits := atomic.LoadUint64(&a.Hits), atomic.LoadUint64(&b.Hits)
+		switch {
+		case aHits > bHits:
+			return -1
+		case aHits < bHits:
+			return 1
+		}
+		return strings.Compare(a.FullPath, b.FullPath)
+	})
+
+	if n < len(nodes) {
+		nodes = nodes[:n]
+	}
+	return nodes
+}
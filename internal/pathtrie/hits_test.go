@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPathTrie_TouchAndTopN(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/users/me", value: 2},
+		pathAndValue{path: "/v1/orders/", value: 3},
+		pathAndValue{path: "/v1/invoices", value: 4},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{
+		"/v1/users/1",
+		"/v1/users/2",
+		"/v1/users/me",
+		"/v1/users/3",
+		"/v1/orders/",
+		"/v1/orders/",
+		"/v1/unknown",
+	} {
+		pt.Touch(path)
+	}
+
+	type args struct {
+		n int
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "top 2",
+			args: args{
+				n: 2,
+			},
+			want: []string{"/v1/users/{id}", "/v1/orders/"},
+		},
+		{
+			name: "more than nodes",
+			args: args{
+				n: 10,
+			},
+			want: []string{"/v1/users/{id}", "/v1/orders/", "/v1/users/me", "/v1/invoices"},
+		},
+		{
+			name: "none",
+			args: args{
+				n: 0,
+			},
+			want: nil,
+		},
+		{
+			name: "negative",
+			args: args{
+				n: -1,
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, node := range pt.TopN(tt.args.n) {
+				got = append(got, node.FullPath)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := pt.TopN(1)[0].Hits; got != 3 {
+		t.Errorf("TopN()[0].Hits = %v, want 3", got)
+	}
+}
+
+func TestPathTrie_WeightedTieBreak(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/{tenant}/users", value: 1},
+		pathAndValue{path: "/v1/{org}/users", value: 2},
+	); err != nil {
+		t.Fatal(err)
+	}
+	org := pt.Trie[""].Children["v1"].Children["{org}"].Children["users"]
+	tenant := pt.Trie[""].Children["v1"].Children["{tenant}"].Children["users"]
+
+	tests := []struct {
+		name             string
+		weightedTieBreak bool
+		orgHits          uint64
+		tenantHits       uint64
+		want             string
+	}{
+		{
+			name:             "lexicographic tie-break by default",
+			weightedTieBreak: false,
+			orgHits:          5,
+			tenantHits:       10,
+			want:             "/v1/{org}/users",
+		},
+		{
+			name:             "most hit node wins",
+			weightedTieBreak: true,
+			orgHits:          5,
+			tenantHits:       10,
+			want:             "/v1/{tenant}/users",
+		},
+		{
+			name:             "lexicographic tie-break on equal hits",
+			weightedTieBreak: true,
+			orgHits:          10,
+			tenantHits:       10,
+			want:             "/v1/{org}/users",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt.WeightedTieBreak = tt.weightedTieBreak
+			org.Hits, tenant.Hits = tt.orgHits, tt.tenantHits
+			if got, _, _ := pt.GetPathAndValue("/v1/acme/users"); got != tt.want {
+				t.Errorf("GetPathAndValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_PathsNotSeenSince(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/users/me", value: 2},
+		pathAndValue{path: "/v1/orders/", value: 3},
+		pathAndValue{path: "/v1/invoices", value: 4},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	pt.getNode("/v1/invoices").LastSeen = now.Add(-40 * 24 * time.Hour).UnixNano()
+	pt.Touch("/v1/users/me")
+	pt.Touch("/v1/orders/")
+
+	if got := pt.getNode("/v1/users/me").LastSeenTime(); got.Before(now) {
+		t.Errorf("LastSeenTime() = %v, want after %v", got, now)
+	}
+	// Only the winning node is touched.
+	if got := pt.getNode("/v1/users/{id}").LastSeenTime(); !got.IsZero() {
+		t.Errorf("LastSeenTime() = %v, want zero", got)
+	}
+
+	type args struct {
+		t time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "not seen in 30 days",
+			args: args{
+				t: now.Add(-30 * 24 * time.Hour),
+			},
+			want: []string{"/v1/invoices", "/v1/users/{id}"},
+		},
+		{
+			name: "not seen in 50 days",
+			args: args{
+				t: now.Add(-50 * 24 * time.Hour),
+			},
+			want: []string{"/v1/users/{id}"},
+		},
+		{
+			name: "not seen since later",
+			args: args{
+				t: time.Now().Add(time.Hour),
+			},
+			want: []string{"/v1/invoices", "/v1/orders/", "/v1/users/me", "/v1/users/{id}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pt.PathsNotSeenSince(tt.args.t); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PathsNotSeenSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"strings"
+)
+
+// GetValueOrInherit resolves path like GetValue, except that the matched node
+// doesn't need to hold a value: if it doesn't, the value of its nearest
+// value-holding ancestor is returned instead, e.g. to store per-prefix
+// defaults. The FullPath of the node holding the value is returned too, and
+// false if path matches no node or neither it nor its ancestors hold a value.
+func (pt *PathTrie) GetValueOrInherit(path string) (any, string, bool) {
+	node := pt.getPrefixNode(path)
+	if node == nil {
+		return nil, "", false
+	}
+
+	chain := pt.nodeChain(node.FullPath)
+	for idx := len(chain) - 1; idx >= 0; idx-- {
+		if chain[idx].Value != nil {
+			return chain[idx].Value, chain[idx].FullPath, true
+		}
+	}
+
+	return nil, "", false
+}
+
+// nodeChain returns the nodes from the root to the node at fullPath, resolved
+// by name.
+func (pt *PathTrie) nodeChain(fullPath string) []*TrieNode {
+	segments := strings.Split(fullPath, pt.PathSeparator)
+
+	var chain []*TrieNode
+	trie := pt.Trie
+	for idx := 0; idx < len(segments); {
+		node, ok := trie[pt.nodeKey(segments[idx])]
+		if !ok {
+			break
+		}
+		chain = append(chain, node)
+		idx += pt.segmentCount(node)
+		trie = node.Children
+	}
+
+	return chain
+}
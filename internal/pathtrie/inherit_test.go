@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathTrie_GetValueOrInherit(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/tenants", value: "100rps"},
+		pathAndValue{path: "/v1/tenants/{tenant}/users/{id}", value: "10rps"},
+		pathAndValue{path: "/v2/orders/{id}/items", value: "5rps"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantVal      any
+		wantFullPath string
+		wantOk       bool
+	}{
+		{
+			name: "value on leaf",
+			args: args{
+				path: "/v1/tenants/acme/users/42",
+			},
+			wantVal:      "10rps",
+			wantFullPath: "/v1/tenants/{tenant}/users/{id}",
+			wantOk:       true,
+		},
+		{
+			name: "value only on ancestor",
+			args: args{
+				path: "/v1/tenants/acme/users",
+			},
+			wantVal:      "100rps",
+			wantFullPath: "/v1/tenants",
+			wantOk:       true,
+		},
+		{
+			name: "value on the node itself",
+			args: args{
+				path: "/v1/tenants",
+			},
+			wantVal:      "100rps",
+			wantFullPath: "/v1/tenants",
+			wantOk:       true,
+		},
+		{
+			name: "no value anywhere",
+			args: args{
+				path: "/v2/orders/1",
+			},
+			wantVal:      nil,
+			wantFullPath: "",
+			wantOk:       false,
+		},
+		{
+			name: "no node",
+			args: args{
+				path: "/v1/unknown",
+			},
+			wantVal:      nil,
+			wantFullPath: "",
+			wantOk:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotVal, gotFullPath, gotOk := pt.GetValueOrInherit(tt.args.path)
+			if !reflect.DeepEqual(gotVal, tt.wantVal) {
+				t.Errorf("GetValueOrInherit() gotVal = %v, want %v", gotVal, tt.wantVal)
+			}
+			if gotFullPath != tt.wantFullPath {
+				t.Errorf("GetValueOrInherit() gotFullPath = %v, want %v", gotFullPath, tt.wantFullPath)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("GetValueOrInherit() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestPathTrie_GetValueOrInherit_compressed(t *testing.T) {
+	pt := New()
+	pt.Insert("/v1", "default")
+	pt.Insert("/v1/a/b/c/d", "leaf")
+	pt.Compress()
+
+	gotVal, gotFullPath, gotOk := pt.GetValueOrInherit("/v1/a/b/c")
+	if gotVal != "default" || gotFullPath != "/v1" || !gotOk {
+		t.Errorf("GetValueOrInherit() = %v, %v, %v, want %v, %v, %v", gotVal, gotFullPath, gotOk, "default", "/v1", true)
+	}
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"iter"
+)
+
+// All returns an iterator over the full path and value of every value-holding
+// node, including the ones ending with a separator, e.g.
+//
+//	for path, val := range pt.All() {
+//		...
+//	}
+//
+// Nodes are yielded lazily in map iteration order, so breaking early skips the
+// rest of the traversal. The trie must not be mutated during the iteration.
+// GetChildren lists the paths of the same traversal, along with the nodes
+// holding no value.
+func (pt *PathTrie) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for node := range pt.nodes(0) {
+			if node.Value != nil && !yield(node.FullPath, node.Value) {
+				return
+			}
+		}
+	}
+}
+
+// nodes returns an iterator over the nodes listed by GetChildrenToDepth, i.e.
+// those with a non-empty FullPath or a value up to maxDepth segments deep,
+// including the empty-name marker children holding the root path or a path
+// ending with a separator. It is the traversal behind All, Walk and
+// GetChildren, so that they agree on the listed paths.
+func (pt *PathTrie) nodes(maxDepth int) iter.Seq[*TrieNode] {
+	return func(yield func(*TrieNode) bool) {
+		for _, rootNode := range pt.Trie {
+			// The empty root segment of absolute paths doesn't count toward depth.
+			depth := 0
+			if rootNode.Name != "" {
+				depth = pt.segmentCount(rootNode)
+			}
+			if !pt.yieldNodes(rootNode, depth, maxDepth, yield) {
+				return
+			}
+		}
+	}
+}
+
+func (pt *PathTrie) yieldNodes(node *TrieNode, depth, maxDepth int, yield func(*TrieNode) bool) bool {
+	// If the node has a FullPath, it represents a complete path.
+	if (node.FullPath != "" || node.Value != nil) && !yield(node) {
+		return false
+	}
+
+	if maxDepth > 0 && depth >= maxDepth {
+		return true
+	}
+
+	for _, childNode := range node.Children {
+		if !pt.yieldNodes(childNode, depth+pt.segmentCount(childNode), maxDepth, yield) {
+			return false
+		}
+	}
+
+	return true
+}
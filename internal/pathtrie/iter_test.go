@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPathTrie_All(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users", value: 1},
+		pathAndValue{path: "/v1/users/{id}", value: 2},
+		pathAndValue{path: "/v1/orders/", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]any)
+	for path, val := range pt.All() {
+		got[path] = val
+	}
+	want := map[string]any{"/v1/users": 1, "/v1/users/{id}": 2, "/v1/orders/": 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+
+	count := 0
+	for range pt.All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("All() yielded %v paths after break, want %v", count, 1)
+	}
+}
+
+func TestPathTrie_All_GetChildren(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/", value: 1},
+		pathAndValue{path: "/v1/users/{id}", value: 2},
+		pathAndValue{path: "/v1/orders/", value: 3},
+		pathAndValue{path: "relative/path", value: 4},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	var all []string
+	for path := range pt.All() {
+		all = append(all, path)
+	}
+	var valuePaths []string
+	for _, path := range pt.GetChildren() {
+		if pt.GetValue(path) != nil {
+			valuePaths = append(valuePaths, path)
+		}
+	}
+	sort.Strings(all)
+	sort.Strings(valuePaths)
+	if !reflect.DeepEqual(all, valuePaths) {
+		t.Errorf("All() = %v, want the value-holding paths of GetChildren() %v", all, valuePaths)
+	}
+}
+
+func TestPathTrie_All_allocations(t *testing.T) {
+	allocs := func(paths int) float64 {
+		pt := New()
+		for i := 0; i < paths; i++ {
+			pt.Insert(fmt.Sprintf("/v1/items/%d", i), i)
+		}
+		return testing.AllocsPerRun(10, func() {
+			for range pt.All() {
+			}
+		})
+	}
+
+	if small, large := allocs(10), allocs(1000); small != large {
+		t.Errorf("All() allocations = %v for 10 paths and %v for 1000 paths, want the same", small, large)
+	}
+}
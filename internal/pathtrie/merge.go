@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+)
+
+// MergeTrie inserts every value-holding path of other into pt, using merge to
+// combine the values of paths present in both tries. ErrSeparatorMismatch is
+// returned if the tries don't use the same path separator.
+func (pt *PathTrie) MergeTrie(other *PathTrie, merge ValueMergeFunc) error {
+	if pt.PathSeparator != other.PathSeparator {
+		return fmt.Errorf("cannot merge tries: %w: `%s` and `%s`", ErrSeparatorMismatch,
+			pt.PathSeparator, other.PathSeparator)
+	}
+
+	for _, rootNode := range other.Trie {
+		walkAll(rootNode, func(node *TrieNode) bool {
+			pt.InsertMerge(node.FullPath, node.Value, merge)
+			return true
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPathTrie_MergeTrie(t *testing.T) {
+	sumMerge := func(existing, newV *any) {
+		*existing = (*existing).(int) + (*newV).(int)
+	}
+
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/orders", value: 2},
+	); err != nil {
+		t.Fatal(err)
+	}
+	other := New()
+	if err := populateDummyPathsAndValue(other,
+		pathAndValue{path: "/v1/users/{id}", value: 10},
+		pathAndValue{path: "/v1/users/{id}/posts", value: 20},
+		pathAndValue{path: "/v1/orders/", value: 30},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pt.MergeTrie(&other, sumMerge); err != nil {
+		t.Fatalf("MergeTrie() error = %v", err)
+	}
+
+	wantValues := map[string]any{
+		"/v1/users/{id}":       11,
+		"/v1/users/{id}/posts": 20,
+		"/v1/orders":           2,
+		"/v1/orders/":          30,
+	}
+	for path, want := range wantValues {
+		if got := pt.GetValue(path); !reflect.DeepEqual(got, want) {
+			t.Errorf("GetValue(%s) = %v, want %v", path, got, want)
+		}
+	}
+
+	gotChildren := pt.GetChildren()
+	sort.Strings(gotChildren)
+	wantChildren := []string{"/v1", "/v1/orders", "/v1/orders/", "/v1/users", "/v1/users/{id}", "/v1/users/{id}/posts"}
+	if !reflect.DeepEqual(gotChildren, wantChildren) {
+		t.Errorf("GetChildren() = %v, want %v", gotChildren, wantChildren)
+	}
+
+	if node := pt.getNode("/v1/users/{id}/posts"); node.PathParamCounter != 1 {
+		t.Errorf("PathParamCounter = %v, want 1", node.PathParamCounter)
+	}
+}
+
+func TestPathTrie_MergeTrie_differentPathSeparators(t *testing.T) {
+	pt := New()
+	other := NewWithPathSeparator(".")
+	other.Insert("v1.users", 1)
+
+	if err := pt.MergeTrie(&other, nil); !errors.Is(err, ErrSeparatorMismatch) {
+		t.Errorf("MergeTrie() error = %v, want %v", err, ErrSeparatorMismatch)
+	}
+	if got := pt.NodeCount(); got != 0 {
+		t.Errorf("NodeCount() = %v, want 0", got)
+	}
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+// Metrics receives the events of a PathTrie, e.g. to export them to a
+// monitoring system, see PathTrie.Metrics. Implementations used by a
+// SafePathTrie must be safe for concurrent use, as lookups run concurrently.
+type Metrics interface {
+	// IncMatch is called by every lookup resolving a path to a node, such as
+	// GetValue, with whether a node matched.
+	IncMatch(hit bool)
+
+	// IncInsert is called by every insertion with whether it created a new path.
+	IncInsert(isNew bool)
+
+	// IncTruncatedMatch is called by every lookup that stopped collecting
+	// matching nodes because of MaxCandidates.
+	IncTruncatedMatch()
+
+	// SetNodeCount is called with the total number of nodes, see NodeCount,
+	// whenever it changes.
+	SetNodeCount(n int)
+}
+
+// NopMetrics is a Metrics discarding every event.
+type NopMetrics struct{}
+
+func (NopMetrics) IncMatch(bool) {}
+
+func (NopMetrics) IncInsert(bool) {}
+
+func (NopMetrics) IncTruncatedMatch() {}
+
+func (NopMetrics) SetNodeCount(int) {}
+
+// addNodes reports the node count changed by delta to Metrics. The node count
+// is computed once when Metrics is first attached, or after
+// invalidateNodeCount, and maintained incrementally from then on.
+func (pt *PathTrie) addNodes(delta int) {
+	if pt.Metrics == nil {
+		pt.nodeCountValid = false
+		return
+	}
+
+	if pt.nodeCountValid {
+		pt.nodeCount += delta
+	} else {
+		pt.nodeCount = pt.NodeCount()
+		pt.nodeCountValid = true
+	}
+	pt.Metrics.SetNodeCount(pt.nodeCount)
+}
+
+// invalidateNodeCount recounts the nodes after a change that isn't tracked
+// incrementally, and reports it to Metrics.
+func (pt *PathTrie) invalidateNodeCount() {
+	pt.nodeCountValid = false
+	pt.addNodes(0)
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"testing"
+)
+
+type recordingMetrics struct {
+	hits, misses       int
+	newPaths, updates  int
+	nodeCount, reports int
+	truncated          int
+}
+
+func (m *recordingMetrics) IncMatch(hit bool) {
+	if hit {
+		m.hits++
+	} else {
+		m.misses++
+	}
+}
+
+func (m *recordingMetrics) IncInsert(isNew bool) {
+	if isNew {
+		m.newPaths++
+	} else {
+		m.updates++
+	}
+}
+
+func (m *recordingMetrics) IncTruncatedMatch() {
+	m.truncated++
+}
+
+func (m *recordingMetrics) SetNodeCount(n int) {
+	m.nodeCount = n
+	m.reports++
+}
+
+func TestPathTrie_Metrics(t *testing.T) {
+	pt := New()
+	pt.Insert("/v1/users", 1)
+
+	metrics := &recordingMetrics{}
+	pt.Metrics = metrics
+
+	pt.Insert("/v1/users/{id}", 2)
+	pt.Insert("/v1/users", 3)
+	pt.Insert("/v1/orders/{id}/items", 4)
+	_ = pt.GetValue("/v1/users/42")
+	_ = pt.GetValue("/v2/users")
+
+	checkNodeCount := func(step string) {
+		t.Helper()
+		if metrics.nodeCount != pt.NodeCount() {
+			t.Errorf("%s: SetNodeCount() = %v, want %v", step, metrics.nodeCount, pt.NodeCount())
+		}
+	}
+	checkNodeCount("insert")
+	if metrics.newPaths != 2 || metrics.updates != 1 {
+		t.Errorf("IncInsert() new = %v, existing = %v, want 2 and 1", metrics.newPaths, metrics.updates)
+	}
+	if metrics.hits != 1 || metrics.misses != 1 {
+		t.Errorf("IncMatch() hits = %v, misses = %v, want 1 and 1", metrics.hits, metrics.misses)
+	}
+	if metrics.reports != 2 {
+		t.Errorf("SetNodeCount() reports = %v, want %v", metrics.reports, 2)
+	}
+
+	pt.Delete("/v1/orders/{id}/items")
+	checkNodeCount("Delete")
+
+	pt.Insert("/v1/static/css/app/main", 5)
+	pt.Compress()
+	checkNodeCount("Compress")
+
+	pt.Insert("/v1/static/js/app", 6)
+	checkNodeCount("insert into compressed node")
+
+	pt.DeleteSubtree("/v1/static")
+	checkNodeCount("DeleteSubtree")
+
+	for i := 0; i < 5; i++ {
+		pt.Insert("/v1/users/"+string(rune('a'+i))+"/avatar", i)
+	}
+	pt.CompactHighCardinality(3, nil)
+	checkNodeCount("CompactHighCardinality")
+}
+
+func TestNopMetrics(t *testing.T) {
+	pt := New()
+	pt.Metrics = NopMetrics{}
+	pt.Insert("/v1/users", 1)
+	if got := pt.GetValue("/v1/users"); got != 1 {
+		t.Errorf("GetValue() = %v, want %v", got, 1)
+	}
+}
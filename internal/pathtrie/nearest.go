@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// ScoredPath is a value-holding path scored against another path by
+// NearestPaths.
+type ScoredPath struct {
+	FullPath string
+	Value    any
+
+	// Score is the number of leading segments of the path aligned with FullPath,
+	// path params matching any segment. The empty leading segment of absolute
+	// paths doesn't count.
+	Score int
+}
+
+// NearestPaths returns up to topN value-holding paths sharing at least one
+// leading segment with path, from the highest to the lowest Score, e.g. to
+// suggest the spec paths closest to an orphan path. Ties are broken by the
+// difference in number of segments with path, then by FullPath, so that the
+// order is deterministic. None are returned if topN isn't positive.
+func (pt *PathTrie) NearestPaths(path string, topN int) []ScoredPath {
+	if topN <= 0 {
+		return nil
+	}
+
+	segments := pt.splitPath(path)
+
+	type candidate struct {
+		ScoredPath
+		lengthDiff int
+	}
+	var candidates []candidate
+	for _, rootNode := range pt.Trie {
+		walkAll(rootNode, func(node *TrieNode) bool {
+			nodeSegments := strings.Split(node.FullPath, pt.PathSeparator)
+			if score := pt.alignedSegments(nodeSegments, segments); score > 0 {
+				candidates = append(candidates, candidate{
+					ScoredPath: ScoredPath{FullPath: node.FullPath, Value: node.Value, Score: score},
+					lengthDiff: max(len(nodeSegments)-len(segments), len(segments)-len(nodeSegments)),
+				})
+			}
+			return true
+		})
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(a.lengthDiff, b.lengthDiff),
+			strings.Compare(a.FullPath, b.FullPath),
+		)
+	})
+
+	var scored []ScoredPath
+	for _, c := range candidates[:min(topN, len(candidates))] {
+		scored = append(scored, c.ScoredPath)
+	}
+
+	return scored
+}
+
+// alignedSegments returns the number of leading segments matched by the node
+// segments, ignoring the empty leading segment of absolute paths. A CatchAll
+// segment matches all the remaining segments.
+func (pt *PathTrie) alignedSegments(nodeSegments, segments []string) int {
+	aligned := 0
+	for idx := 0; idx < len(nodeSegments) && idx < len(segments); idx++ {
+		name := nodeSegments[idx]
+		if name == CatchAll {
+			return aligned + len(segments) - idx
+		}
+		if !pt.isSegmentMatch(name, segments[idx]) {
+			break
+		}
+		if idx > 0 || name != "" {
+			aligned++
+		}
+	}
+
+	return aligned
+}
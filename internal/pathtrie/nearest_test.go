@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathTrie_NearestPaths(t *testing.T) {
+	spec := New()
+	if err := populateDummyPathsAndValue(spec,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/users/{id}/posts", value: 2},
+		pathAndValue{path: "/v1/users/{id}/avatar", value: 3},
+		pathAndValue{path: "/v1/users", value: 4},
+		pathAndValue{path: "/v1/orders", value: 5},
+		pathAndValue{path: "/v2/users/{id}/posts", value: 6},
+		pathAndValue{path: "/v1/files/**", value: 7},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		path string
+		topN int
+	}
+	tests := []struct {
+		name string
+		args args
+		want []ScoredPath
+	}{
+		{
+			name: "orphan sub-resource",
+			args: args{
+				path: "/v1/users/42/comments",
+				topN: 4,
+			},
+			want: []ScoredPath{
+				{FullPath: "/v1/users/{id}/avatar", Value: 3, Score: 3},
+				{FullPath: "/v1/users/{id}/posts", Value: 2, Score: 3},
+				{FullPath: "/v1/users/{id}", Value: 1, Score: 3},
+				{FullPath: "/v1/users", Value: 4, Score: 2},
+			},
+		},
+		{
+			name: "catch-all",
+			args: args{
+				path: "/v1/files/a/b",
+				topN: 2,
+			},
+			want: []ScoredPath{
+				{FullPath: "/v1/files/**", Value: 7, Score: 4},
+				{FullPath: "/v1/users/{id}/avatar", Value: 3, Score: 1},
+			},
+		},
+		{
+			name: "no shared segment",
+			args: args{
+				path: "/v3/health",
+				topN: 3,
+			},
+			want: nil,
+		},
+		{
+			name: "top 0",
+			args: args{
+				path: "/v1/users",
+				topN: 0,
+			},
+			want: nil,
+		},
+		{
+			name: "negative top",
+			args: args{
+				path: "/v1/users",
+				topN: -1,
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spec.NearestPaths(tt.args.path, tt.args.topN); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NearestPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
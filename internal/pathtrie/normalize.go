@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/5gsec/api-speculator/internal/util"
+)
+
+// Normalize returns the canonical form of path under the normalization options
+// of the PathTrie, i.e. StripQueryAndFragment, CollapseEmptySegments,
+// TrimTrailingSeparator, DecodeSegments, EscapeByte, NormalizeUnicode,
+// CaseInsensitive and UnifyPathParams, e.g. to log it alongside the raw path.
+// Insert and lookups go through the same steps, so two paths with the same
+// canonical form reach the same node.
+func (pt *PathTrie) Normalize(path string) string {
+	segments := pt.splitPath(path)
+	for idx, segment := range segments {
+		segments[idx] = pt.nodeKey(segment)
+	}
+
+	return strings.Join(segments, pt.PathSeparator)
+}
+
+// splitPath splits path into segments after applying the normalization options
+// of the PathTrie. The empty path is the root path, i.e. the bare separator.
+func (pt *PathTrie) splitPath(path string) []string {
+	if pt.StripQueryAndFragment {
+		path = pt.stripQueryAndFragment(path)
+	}
+	if path == "" {
+		path = pt.PathSeparator
+	}
+
+	segments := pt.split(path)
+	if pt.CollapseEmptySegments {
+		segments = collapseEmptySegments(segments)
+	}
+
+	// Trim the trailing empty segment, unless the path is the bare separator.
+	last := len(segments) - 1
+	isRoot := len(segments) == 2 && segments[0] == ""
+	if pt.TrimTrailingSeparator && last > 0 && segments[last] == "" && !isRoot {
+		segments = segments[:last]
+	}
+
+	if pt.DecodeSegments {
+		for idx, segment := range segments {
+			segments[idx] = pt.decodeSegment(segment)
+		}
+	}
+	if pt.NormalizeUnicode {
+		for idx, segment := range segments {
+			segments[idx] = norm.NFC.String(segment)
+		}
+	}
+
+	return segments
+}
+
+// stripQueryAndFragment removes the query and fragment of path, ignoring the
+// '?' and '#' that are part of the separator.
+func (pt *PathTrie) stripQueryAndFragment(path string) string {
+	if idx := strings.IndexFunc(path, func(r rune) bool {
+		return (r == '?' || r == '#') && !strings.ContainsRune(pt.PathSeparator, r)
+	}); idx != -1 {
+		return path[:idx]
+	}
+
+	return path
+}
+
+// decodeSegment percent-decodes segment, keeping the encoded separator encoded
+// so that the decoded segment doesn't split differently afterward.
+func (pt *PathTrie) decodeSegment(segment string) string {
+	decoded := util.NormalizeSegment(segment)
+	if pt.PathSeparator == "" || !strings.Contains(decoded, pt.PathSeparator) {
+		return decoded
+	}
+	if !pt.canEncodeSeparator() {
+		return segment
+	}
+
+	return strings.ReplaceAll(decoded, pt.PathSeparator, pt.encodedSeparator())
+}
+
+// encodedSeparator returns the percent-encoded separator, e.g. %3A%3A for ::.
+func (pt *PathTrie) encodedSeparator() string {
+	var sb strings.Builder
+	for idx := 0; idx < len(pt.PathSeparator); idx++ {
+		fmt.Fprintf(&sb, "%%%02X", pt.PathSeparator[idx])
+	}
+
+	return sb.String()
+}
+
+// canEncodeSeparator reports whether the encoded separator doesn't contain the
+// separator, which isn't the case of e.g. 2 or %.
+func (pt *PathTrie) canEncodeSeparator() bool {
+	return !strings.Contains(pt.encodedSeparator(), pt.PathSeparator)
+}
+
+// encodeSeparators returns segments with the separators they hold
+// percent-encoded, copying segments only if needed, and false if the separator
+// can't be encoded.
+func (pt *PathTrie) encodeSeparators(segments []string) ([]string, bool) {
+	if pt.PathSeparator == "" {
+		return segments, true
+	}
+
+	encoded, copied := segments, false
+	for idx, segment := range segments {
+		if !strings.Contains(segment, pt.PathSeparator) {
+			continue
+		}
+		if !pt.canEncodeSeparator() {
+			return nil, false
+		}
+		if !copied {
+			encoded, copied = slices.Clone(segments), true
+		}
+		encoded[idx] = strings.ReplaceAll(segment, pt.PathSeparator, pt.encodedSeparator())
+	}
+
+	return encoded, true
+}
+
+// split splits path on the separators that aren't preceded by EscapeByte, if
+// set. Escaped separators are percent-encoded in their segment.
+func (pt *PathTrie) split(path string) []string {
+	if pt.EscapeByte == 0 || pt.PathSeparator == "" || !pt.canEncodeSeparator() {
+		return strings.Split(path, pt.PathSeparator)
+	}
+
+	escapedSeparator := string(pt.EscapeByte) + pt.PathSeparator
+	if !strings.Contains(path, escapedSeparator) {
+		return strings.Split(path, pt.PathSeparator)
+	}
+	encodedSeparator := pt.encodedSeparator()
+
+	var segments []string
+	var segment strings.Builder
+	for len(path) > 0 {
+		switch {
+		case strings.HasPrefix(path, escapedSeparator):
+			segment.WriteString(encodedSeparator)
+			path = path[len(escapedSeparator):]
+		case strings.HasPrefix(path, pt.PathSeparator):
+			segments = append(segments, segment.String())
+			segment.Reset()
+			path = path[len(pt.PathSeparator):]
+		default:
+			segment.WriteByte(path[0])
+			path = path[1:]
+		}
+	}
+
+	return append(segments, segment.String())
+}
+
+// foldCase returns the case-folded form of segment, rune by rune, so that all
+// the segments that only differ by case share it, e.g. \u03c3 and \u03c2.
+func foldCase(segment string) string {
+	return strings.Map(func(r rune) rune {
+		return unicode.ToLower(unicode.ToUpper(r))
+	}, segment)
+}
+
+// collapseEmptySegments drops the empty segments produced by consecutive
+// separators. The leading empty segment of absolute paths and the trailing one
+// of paths ending with a separator are kept.
+func collapseEmptySegments(segments []string) []string {
+	if len(segments) <= 2 {
+		return segments
+	}
+
+	collapsed := segments[:1]
+	for _, segment := range segments[1 : len(segments)-1] {
+		if segment != "" {
+			collapsed = append(collapsed, segment)
+		}
+	}
+
+	return append(collapsed, segments[len(segments)-1])
+}
@@ -0,0 +1,811 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPathTrie_splitPath(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name                  string
+		trimTrailingSeparator bool
+		collapseEmptySegments bool
+		stripQueryAndFragment bool
+		decodeSegments        bool
+		args                  args
+		want                  []string
+	}{
+		{
+			name: "root is kept by default",
+			args: args{
+				path: "/",
+			},
+			want: []string{"", ""},
+		},
+		{
+			name:                  "root is kept when trimming",
+			trimTrailingSeparator: true,
+			args: args{
+				path: "/",
+			},
+			want: []string{"", ""},
+		},
+		{
+			name: "trailing separator is kept by default",
+			args: args{
+				path: "/a/",
+			},
+			want: []string{"", "a", ""},
+		},
+		{
+			name:                  "trailing separator is trimmed",
+			trimTrailingSeparator: true,
+			args: args{
+				path: "/a/",
+			},
+			want: []string{"", "a"},
+		},
+		{
+			name:                  "only a single trailing separator is trimmed",
+			trimTrailingSeparator: true,
+			args: args{
+				path: "/a//",
+			},
+			want: []string{"", "a", ""},
+		},
+		{
+			name:                  "inner empty segment is kept",
+			trimTrailingSeparator: true,
+			args: args{
+				path: "/a//b",
+			},
+			want: []string{"", "a", "", "b"},
+		},
+		{
+			name:                  "inner empty segments are collapsed",
+			collapseEmptySegments: true,
+			args: args{
+				path: "/v1//users///{id}",
+			},
+			want: []string{"", "v1", "users", "{id}"},
+		},
+		{
+			name:                  "trailing empty segment is kept when collapsing",
+			collapseEmptySegments: true,
+			args: args{
+				path: "/v1//users//",
+			},
+			want: []string{"", "v1", "users", ""},
+		},
+		{
+			name:                  "doubled root collapses to root",
+			collapseEmptySegments: true,
+			args: args{
+				path: "//",
+			},
+			want: []string{"", ""},
+		},
+		{
+			name:                  "collapse and trim",
+			trimTrailingSeparator: true,
+			collapseEmptySegments: true,
+			args: args{
+				path: "/v1//users//",
+			},
+			want: []string{"", "v1", "users"},
+		},
+		{
+			name: "query is kept by default",
+			args: args{
+				path: "/v1/users?expand=true",
+			},
+			want: []string{"", "v1", "users?expand=true"},
+		},
+		{
+			name:                  "query is stripped",
+			stripQueryAndFragment: true,
+			args: args{
+				path: "/v1/users?expand=true&next=/v2",
+			},
+			want: []string{"", "v1", "users"},
+		},
+		{
+			name:                  "fragment is stripped",
+			stripQueryAndFragment: true,
+			args: args{
+				path: "/v1/users/42#x",
+			},
+			want: []string{"", "v1", "users", "42"},
+		},
+		{
+			name:                  "query and fragment are stripped",
+			stripQueryAndFragment: true,
+			args: args{
+				path: "/v1/users/42?expand=true#x",
+			},
+			want: []string{"", "v1", "users", "42"},
+		},
+		{
+			name:                  "strip then trim",
+			trimTrailingSeparator: true,
+			stripQueryAndFragment: true,
+			args: args{
+				path: "/v1/users/?expand=true",
+			},
+			want: []string{"", "v1", "users"},
+		},
+		{
+			name: "segments are not decoded by default",
+			args: args{
+				path: "/files/my%20file",
+			},
+			want: []string{"", "files", "my%20file"},
+		},
+		{
+			name:           "segments are decoded",
+			decodeSegments: true,
+			args: args{
+				path: "/files/my%20file",
+			},
+			want: []string{"", "files", "my file"},
+		},
+		{
+			name:           "encoded separator doesn't split the segment",
+			decodeSegments: true,
+			args: args{
+				path: "/files/a%2fb",
+			},
+			want: []string{"", "files", "a%2Fb"},
+		},
+		{
+			name: "empty path is the root path",
+			args: args{
+				path: "",
+			},
+			want: []string{"", ""},
+		},
+		{
+			name:           "malformed escape is kept",
+			decodeSegments: true,
+			args: args{
+				path: "/files/a%zz",
+			},
+			want: []string{"", "files", "a%zz"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			pt.TrimTrailingSeparator = tt.trimTrailingSeparator
+			pt.CollapseEmptySegments = tt.collapseEmptySegments
+			pt.StripQueryAndFragment = tt.stripQueryAndFragment
+			pt.DecodeSegments = tt.decodeSegments
+			if got := pt.splitPath(tt.args.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_TrimTrailingSeparator(t *testing.T) {
+	pt := New()
+	pt.TrimTrailingSeparator = true
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/", value: 1},
+		pathAndValue{path: "/a/", value: 2},
+		pathAndValue{path: "/a//b", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path      string
+		wantPath  string
+		wantValue any
+		wantFound bool
+	}{
+		{path: "/", wantPath: "/", wantValue: 1, wantFound: true},
+		{path: "", wantPath: "/", wantValue: 1, wantFound: true},
+		{path: "/a", wantPath: "/a", wantValue: 2, wantFound: true},
+		{path: "/a/", wantPath: "/a", wantValue: 2, wantFound: true},
+		{path: "/a//b", wantPath: "/a//b", wantValue: 3, wantFound: true},
+		{path: "/a//b/", wantPath: "/a//b", wantValue: 3, wantFound: true},
+		{path: "/a/b", wantFound: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			gotPath, gotValue, gotFound := pt.GetPathAndValue(tt.path)
+			if gotPath != tt.wantPath || !reflect.DeepEqual(gotValue, tt.wantValue) || gotFound != tt.wantFound {
+				t.Errorf("GetPathAndValue() = (%v, %v, %v), want (%v, %v, %v)",
+					gotPath, gotValue, gotFound, tt.wantPath, tt.wantValue, tt.wantFound)
+			}
+		})
+	}
+
+	if !pt.Delete("/a/") {
+		t.Error("Delete(/a/) = false, want true")
+	}
+	if _, found := pt.GetValueOK("/a"); found {
+		t.Error("GetValueOK(/a) found after Delete(/a/)")
+	}
+}
+
+func TestPathTrie_CollapseEmptySegments(t *testing.T) {
+	pt := New()
+	pt.CollapseEmptySegments = true
+	if !pt.Insert("/v1//users", 1) {
+		t.Fatal("Insert(/v1//users) = false, want true")
+	}
+	if pt.Insert("/v1/users", 2) {
+		t.Error("Insert(/v1/users) = true, want false")
+	}
+
+	for _, path := range []string{"/v1/users", "/v1//users", "/v1///users"} {
+		gotPath, gotValue, gotFound := pt.GetPathAndValue(path)
+		if gotPath != "/v1/users" || gotValue != 2 || !gotFound {
+			t.Errorf("GetPathAndValue(%s) = (%v, %v, %v), want (/v1/users, 2, true)", path, gotPath, gotValue, gotFound)
+		}
+	}
+
+	pt.CollapseEmptySegments = false
+	if _, found := pt.GetValueOK("/v1//users"); found {
+		t.Error("GetValueOK(/v1//users) found with CollapseEmptySegments disabled")
+	}
+}
+
+func TestPathTrie_StripQueryAndFragment(t *testing.T) {
+	pt := New()
+	pt.StripQueryAndFragment = true
+	if !pt.Insert("/v1/users/{id}?expand=true", 1) {
+		t.Fatal("Insert(/v1/users/{id}?expand=true) = false, want true")
+	}
+	if pt.Insert("/v1/users/{id}#x", 2) {
+		t.Error("Insert(/v1/users/{id}#x) = true, want false")
+	}
+
+	for _, path := range []string{"/v1/users/42", "/v1/users/42?expand=true", "/v1/users/42#x", "/v1/users/42?expand=true#x"} {
+		gotPath, gotValue, gotFound := pt.GetPathAndValue(path)
+		if gotPath != "/v1/users/{id}" || gotValue != 2 || !gotFound {
+			t.Errorf("GetPathAndValue(%s) = (%v, %v, %v), want (/v1/users/{id}, 2, true)", path, gotPath, gotValue, gotFound)
+		}
+	}
+}
+
+func TestPathTrie_DecodeSegments(t *testing.T) {
+	pt := New()
+	pt.DecodeSegments = true
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/files/a%2Fb", value: 1},
+		pathAndValue{path: "/files/a/b", value: 2},
+		pathAndValue{path: "/files/my%20file", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path      string
+		wantPath  string
+		wantValue any
+	}{
+		{path: "/files/a%2Fb", wantPath: "/files/a%2Fb", wantValue: 1},
+		{path: "/files/a%2fb", wantPath: "/files/a%2Fb", wantValue: 1},
+		{path: "/files/a/b", wantPath: "/files/a/b", wantValue: 2},
+		{path: "/files/my file", wantPath: "/files/my file", wantValue: 3},
+		{path: "/files/my%20file", wantPath: "/files/my file", wantValue: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			gotPath, gotValue, _ := pt.GetPathAndValue(tt.path)
+			if gotPath != tt.wantPath || gotValue != tt.wantValue {
+				t.Errorf("GetPathAndValue() = (%v, %v), want (%v, %v)", gotPath, gotValue, tt.wantPath, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestPathTrie_NormalizeUnicode(t *testing.T) {
+	pt := New()
+	pt.NormalizeUnicode = true
+	pt.DecodeSegments = true
+	pt.CaseInsensitive = true
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/menu/caf\u00e9", value: 1},
+		pathAndValue{path: "/menu/\u03c3\u03bf\u03c5\u03c0\u03b1", value: 2},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path      string
+		wantPath  string
+		wantValue any
+	}{
+		{path: "/menu/caf\u00e9", wantPath: "/menu/caf\u00e9", wantValue: 1},
+		{path: "/menu/cafe\u0301", wantPath: "/menu/caf\u00e9", wantValue: 1},
+		{path: "/menu/caf%C3%A9", wantPath: "/menu/caf\u00e9", wantValue: 1},
+		{path: "/menu/cafe%CC%81", wantPath: "/menu/caf\u00e9", wantValue: 1},
+		{path: "/menu/CAF\u00c9", wantPath: "/menu/caf\u00e9", wantValue: 1},
+		{path: "/menu/CAFE\u0301", wantPath: "/menu/caf\u00e9", wantValue: 1},
+		{path: "/menu/\u03a3\u039f\u03a5\u03a0\u0391", wantPath: "/menu/\u03c3\u03bf\u03c5\u03c0\u03b1", wantValue: 2},
+		{path: "/menu/\u03c2\u03bf\u03c5\u03c0\u03b1", wantPath: "/menu/\u03c3\u03bf\u03c5\u03c0\u03b1", wantValue: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			gotPath, gotValue, _ := pt.GetPathAndValue(tt.path)
+			if gotPath != tt.wantPath || gotValue != tt.wantValue {
+				t.Errorf("GetPathAndValue() = (%v, %v), want (%v, %v)", gotPath, gotValue, tt.wantPath, tt.wantValue)
+			}
+		})
+	}
+
+	count := pt.NodeCount()
+	if pt.Insert("/menu/cafe\u0301", 3) {
+		t.Error("Insert() = true, want false")
+	}
+	if got := pt.NodeCount(); got != count {
+		t.Errorf("NodeCount() = %v, want %v", got, count)
+	}
+}
+
+func TestPathTrie_NormalizeUnicode_disabled(t *testing.T) {
+	pt := New()
+	pt.Insert("/menu/caf\u00e9", 1)
+	if got := pt.GetValue("/menu/cafe\u0301"); got != nil {
+		t.Errorf("GetValue() = %v, want nil", got)
+	}
+}
+
+func Test_foldCase(t *testing.T) {
+	type args struct {
+		segment string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "ascii",
+			args: args{
+				segment: "Users",
+			},
+			want: "users",
+		},
+		{
+			name: "accented",
+			args: args{
+				segment: "CAF\u00c9",
+			},
+			want: "caf\u00e9",
+		},
+		{
+			name: "final sigma",
+			args: args{
+				segment: "\u03c2",
+			},
+			want: "\u03c3",
+		},
+		{
+			name: "kelvin sign",
+			args: args{
+				segment: "\u212a",
+			},
+			want: "k",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := foldCase(tt.args.segment); got != tt.want {
+				t.Errorf("foldCase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_EscapeByte(t *testing.T) {
+	pt := New()
+	pt.EscapeByte = '\\'
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: `/files/\/etc`, value: 1},
+		pathAndValue{path: `/files/a\/b/raw`, value: 2},
+		pathAndValue{path: `/files/tmp\/`, value: 3},
+		pathAndValue{path: `/files/a/b/raw`, value: 4},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantFullPath string
+		wantVal      any
+	}{
+		{
+			name: "escaped separator at segment start",
+			args: args{
+				path: `/files/\/etc`,
+			},
+			wantFullPath: "/files/%2Fetc",
+			wantVal:      1,
+		},
+		{
+			name: "escaped separator in segment middle",
+			args: args{
+				path: `/files/a\/b/raw`,
+			},
+			wantFullPath: "/files/a%2Fb/raw",
+			wantVal:      2,
+		},
+		{
+			name: "escaped separator at segment end",
+			args: args{
+				path: `/files/tmp\/`,
+			},
+			wantFullPath: "/files/tmp%2F",
+			wantVal:      3,
+		},
+		{
+			name: "unescaped separators split",
+			args: args{
+				path: "/files/a/b/raw",
+			},
+			wantFullPath: "/files/a/b/raw",
+			wantVal:      4,
+		},
+		{
+			name: "percent-encoded separator is the same segment",
+			args: args{
+				path: "/files/a%2Fb/raw",
+			},
+			wantFullPath: "/files/a%2Fb/raw",
+			wantVal:      2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFullPath, gotVal, _ := pt.GetPathAndValue(tt.args.path)
+			if gotFullPath != tt.wantFullPath {
+				t.Errorf("GetPathAndValue() gotFullPath = %v, want %v", gotFullPath, tt.wantFullPath)
+			}
+			if gotVal != tt.wantVal {
+				t.Errorf("GetPathAndValue() gotVal = %v, want %v", gotVal, tt.wantVal)
+			}
+		})
+	}
+}
+
+func TestPathTrie_split(t *testing.T) {
+	type args struct {
+		separator  string
+		escapeByte byte
+		path       string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "no escape byte",
+			args: args{
+				separator: "/",
+				path:      `/a\/b`,
+			},
+			want: []string{"", `a\`, "b"},
+		},
+		{
+			name: "escape byte not followed by separator is kept",
+			args: args{
+				separator:  "/",
+				escapeByte: '\\',
+				path:       `/a\b\/c`,
+			},
+			want: []string{"", `a\b%2Fc`},
+		},
+		{
+			name: "multi-char separator",
+			args: args{
+				separator:  "::",
+				escapeByte: '!',
+				path:       "a!::b::c",
+			},
+			want: []string{"a%3A%3Ab", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := NewWithPathSeparator(tt.args.separator)
+			pt.EscapeByte = tt.args.escapeByte
+			if got := pt.split(tt.args.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("split() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_separators(t *testing.T) {
+	for _, sep := range []string{"/", ".", "::"} {
+		// join builds a path from segments with the separator under test.
+		join := func(segments ...string) string {
+			return strings.Join(segments, sep)
+		}
+
+		t.Run(sep, func(t *testing.T) {
+			pt := NewWithPathSeparator(sep)
+			pt.TrimTrailingSeparator = true
+			pt.StripQueryAndFragment = true
+			pt.DecodeSegments = true
+			if err := populateDummyPathsAndValue(pt,
+				pathAndValue{path: join("", "v1", "users", "{id}", "orders", "{orderId}"), value: 1},
+				pathAndValue{path: join("", "v1", "users"), value: 2},
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			tests := []struct {
+				name         string
+				path         string
+				wantFullPath string
+				wantVal      any
+			}{
+				{
+					name:         "path params",
+					path:         join("", "v1", "users", "42", "orders", "7"),
+					wantFullPath: join("", "v1", "users", "{id}", "orders", "{orderId}"),
+					wantVal:      1,
+				},
+				{
+					name:         "trailing separator",
+					path:         join("", "v1", "users", ""),
+					wantFullPath: join("", "v1", "users"),
+					wantVal:      2,
+				},
+				{
+					name:         "query and fragment",
+					path:         join("", "v1", "users") + "?limit=10#top",
+					wantFullPath: join("", "v1", "users"),
+					wantVal:      2,
+				},
+				{
+					name:         "encoded separator",
+					path:         join("", "v1", "users", "a"+strings.ToLower(pt.encodedSeparator())+"b", "orders", "7"),
+					wantFullPath: join("", "v1", "users", "{id}", "orders", "{orderId}"),
+					wantVal:      1,
+				},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					gotFullPath, gotVal, _ := pt.GetPathAndValue(tt.path)
+					if gotFullPath != tt.wantFullPath {
+						t.Errorf("GetPathAndValue() gotFullPath = %v, want %v", gotFullPath, tt.wantFullPath)
+					}
+					if gotVal != tt.wantVal {
+						t.Errorf("GetPathAndValue() gotVal = %v, want %v", gotVal, tt.wantVal)
+					}
+				})
+			}
+
+			node := pt.getNode(join("", "v1", "users", "42", "orders", "7"))
+			if node == nil || node.PathParamCounter != 2 {
+				t.Errorf("getNode() = %v, want a node with PathParamCounter 2", node)
+			}
+
+			pt.Compress()
+			if gotFullPath, _, _ := pt.GetPathAndValue(join("", "v1", "users", "42", "orders", "7")); gotFullPath != join("", "v1", "users", "{id}", "orders", "{orderId}") {
+				t.Errorf("GetPathAndValue() after Compress() gotFullPath = %v", gotFullPath)
+			}
+		})
+	}
+}
+
+func TestPathTrie_decodeSegment(t *testing.T) {
+	type args struct {
+		separator string
+		segment   string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "encoded slash",
+			args: args{
+				separator: "/",
+				segment:   "a%2fb%20c",
+			},
+			want: "a%2Fb c",
+		},
+		{
+			name: "encoded dot",
+			args: args{
+				separator: ".",
+				segment:   "a%2eb%20c",
+			},
+			want: "a%2Eb c",
+		},
+		{
+			name: "encoded double colon",
+			args: args{
+				separator: "::",
+				segment:   "a%3a%3ab%3Ac",
+			},
+			want: "a%3A%3Ab:c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := NewWithPathSeparator(tt.args.separator)
+			if got := pt.decodeSegment(tt.args.segment); got != tt.want {
+				t.Errorf("decodeSegment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_Normalize(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name   string
+		config func(pt *PathTrie)
+		args   args
+		want   string
+	}{
+		{
+			name:   "no options",
+			config: func(*PathTrie) {},
+			args: args{
+				path: "/V1//Users/?limit=1",
+			},
+			want: "/V1//Users/?limit=1",
+		},
+		{
+			name: "all options",
+			config: func(pt *PathTrie) {
+				pt.StripQueryAndFragment = true
+				pt.CollapseEmptySegments = true
+				pt.TrimTrailingSeparator = true
+				pt.DecodeSegments = true
+				pt.CaseInsensitive = true
+				pt.UnifyPathParams = true
+			},
+			args: args{
+				path: "/V1//Users/{userId}/Files%20Shared/a%2fb/?limit=1#top",
+			},
+			want: "/v1/users/{param}/files shared/a%2fb",
+		},
+		{
+			name: "case insensitive keeps path params",
+			config: func(pt *PathTrie) {
+				pt.CaseInsensitive = true
+			},
+			args: args{
+				path: "/V1/Users/{userId}",
+			},
+			want: "/v1/users/{userId}",
+		},
+		{
+			name: "escaped separator",
+			config: func(pt *PathTrie) {
+				pt.EscapeByte = '\\'
+			},
+			args: args{
+				path: `/files/a\/b`,
+			},
+			want: "/files/a%2Fb",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			tt.config(&pt)
+			if got := pt.Normalize(tt.args.path); got != tt.want {
+				t.Errorf("Normalize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_Normalize_sameNode(t *testing.T) {
+	pt := New()
+	pt.TrimTrailingSeparator = true
+	pt.CollapseEmptySegments = true
+	pt.CaseInsensitive = true
+	pt.Insert("/v1/Users/", 1)
+
+	for _, path := range []string{"/V1//users", "/v1/USERS/"} {
+		if got, want := pt.Normalize(path), pt.Normalize("/v1/Users/"); got != want {
+			t.Errorf("Normalize(%s) = %v, want %v", path, got, want)
+		}
+		if got := pt.GetValue(path); got != 1 {
+			t.Errorf("GetValue(%s) = %v, want 1", path, got)
+		}
+	}
+}
+
+func TestPathTrie_separatorInEncodedForm(t *testing.T) {
+	// The percent-encoded 2 is %32, so it can't be escaped nor kept encoded.
+	pt := NewWithPathSeparator("2")
+	pt.EscapeByte = '\\'
+	pt.DecodeSegments = true
+
+	if got, want := pt.splitPath(`a\2b%32c`), []string{`a\`, "b%3", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitPath() = %q, want %q", got, want)
+	}
+	if !pt.Insert(`\2`, 1) {
+		t.Fatal("Insert() = false, want true")
+	}
+	if got := pt.GetValue(`\2`); got != 1 {
+		t.Errorf("GetValue() = %v, want 1", got)
+	}
+}
+
+func TestPathTrie_root(t *testing.T) {
+	type args struct {
+		insertPath string
+	}
+	tests := []struct {
+		name string
+		sep  string
+		args args
+	}{
+		{
+			name: "bare separator",
+			sep:  "/",
+			args: args{
+				insertPath: "/",
+			},
+		},
+		{
+			name: "empty path",
+			sep:  "/",
+			args: args{
+				insertPath: "",
+			},
+		},
+		{
+			name: "multi-char separator",
+			sep:  "::",
+			args: args{
+				insertPath: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := NewWithPathSeparator(tt.sep)
+			pt.Insert("/health", "health")
+			if !pt.Insert(tt.args.insertPath, "root") {
+				t.Fatalf("Insert(%q) = false, want true", tt.args.insertPath)
+			}
+
+			for _, path := range []string{"", tt.sep} {
+				gotPath, gotVal, ok := pt.GetPathAndValue(path)
+				if !ok || gotPath != tt.sep || gotVal != "root" {
+					t.Errorf("GetPathAndValue(%q) = %v, %v, %v, want %v, root, true", path, gotPath, gotVal, ok, tt.sep)
+				}
+			}
+			if pt.Insert(tt.sep, "root") {
+				t.Errorf("Insert(%q) = true, want the root path to be overwritten", tt.sep)
+			}
+			if !pt.Delete("") {
+				t.Errorf("Delete(\"\") = false, want true")
+			}
+			if _, ok := pt.GetValueOK(tt.sep); ok {
+				t.Errorf("GetValueOK(%q) found the deleted root path", tt.sep)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"slices"
+)
+
+// ObservedValues returns the distinct segments that were mapped onto the path
+// param node, in insertion order, e.g. [42 99 7] for {id}. See
+// PathTrie.MaxObservedValues.
+func (node *TrieNode) ObservedValues() []string {
+	return slices.Clone(node.observedValues)
+}
+
+// observeParam returns the path param node of trie onto which segment is
+// mapped, recording segment, or nil if there is none.
+func (pt *PathTrie) observeParam(trie PathToTrieNode, segment string) *TrieNode {
+	if pt.isPathParam(segment) {
+		return nil
+	}
+
+	var param *TrieNode
+	for _, node := range trie {
+		if node.isCatchAll() || !pt.isPathParam(node.Name) {
+			continue
+		}
+		if param == nil || node.Name < param.Name {
+			param = node
+		}
+	}
+	if param == nil {
+		return nil
+	}
+
+	if len(param.observedValues) < pt.MaxObservedValues && !slices.Contains(param.observedValues, segment) {
+		param.observedValues = append(param.observedValues, segment)
+	}
+
+	return param
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTrieNode_ObservedValues(t *testing.T) {
+	pt := New()
+	pt.MaxObservedValues = 3
+	pt.Insert("/v1/users/me", 1)
+	pt.Insert("/v1/users/{id}", 2)
+	for idx, path := range []string{"/v1/users/42", "/v1/users/99/posts", "/v1/users/42", "/v1/users/7", "/v1/users/8"} {
+		pt.Insert(path, idx+3)
+	}
+
+	param := pt.getNode("/v1/users/{id}")
+	// The static /v1/users/me sibling inserted first matches me, and the samples
+	// are capped.
+	if got, want := param.ObservedValues(), []string{"42", "99", "7"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ObservedValues() = %v, want %v", got, want)
+	}
+	if got := param.Value; got != 7 {
+		t.Errorf("Value = %v, want 7", got)
+	}
+	if got := pt.GetValue("/v1/users/me"); got != 1 {
+		t.Errorf("GetValue(/v1/users/me) = %v, want 1", got)
+	}
+	wantChildren := []string{"/v1", "/v1/users", "/v1/users/me", "/v1/users/{id}", "/v1/users/{id}/posts"}
+	gotChildren := pt.GetChildren()
+	sort.Strings(gotChildren)
+	if !reflect.DeepEqual(gotChildren, wantChildren) {
+		t.Errorf("GetChildren() = %v, want %v", gotChildren, wantChildren)
+	}
+
+	// The returned samples are a copy.
+	param.ObservedValues()[0] = "0"
+	if got := param.ObservedValues()[0]; got != "42" {
+		t.Errorf("ObservedValues()[0] = %v, want 42", got)
+	}
+}
+
+func TestTrieNode_ObservedValues_disabled(t *testing.T) {
+	pt := New()
+	pt.Insert("/v1/users/{id}", 1)
+	pt.Insert("/v1/users/42", 2)
+
+	if got := pt.getNode("/v1/users/{id}").ObservedValues(); len(got) != 0 {
+		t.Errorf("ObservedValues() = %v, want none", got)
+	}
+	if got := pt.GetValue("/v1/users/42"); got != 2 {
+		t.Errorf("GetValue() = %v, want 2", got)
+	}
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPIInfo is the info object of the documents generated by ToOpenAPI3.
+type OpenAPIInfo struct {
+	Title       string `yaml:"title"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type openAPIDocument struct {
+	OpenAPI string                     `yaml:"openapi"`
+	Info    OpenAPIInfo                `yaml:"info"`
+	Paths   map[string]openAPIPathItem `yaml:"paths"`
+}
+
+type openAPIPathItem struct {
+	Parameters []openAPIParameter          `yaml:"parameters,omitempty"`
+	Operations map[string]openAPIOperation `yaml:",inline"`
+}
+
+type openAPIParameter struct {
+	Name     string            `yaml:"name"`
+	In       string            `yaml:"in"`
+	Required bool              `yaml:"required"`
+	Schema   map[string]string `yaml:"schema"`
+}
+
+type openAPIOperation struct {
+	Responses map[string]openAPIResponse `yaml:"responses"`
+}
+
+type openAPIResponse struct {
+	Description string `yaml:"description"`
+}
+
+// openAPIMethods are the operations allowed in OpenAPI 3 path items.
+var openAPIMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// ToOpenAPI3 returns a minimal OpenAPI 3.0 YAML document with a path item for
+// each value-holding node. Param segments become `{name}` templates declared
+// as string path params, and if the value is a map[string]any keyed by HTTP
+// method, like for MatchOperation, each method becomes an operation with a
+// stubbed default response. The operations of distinct paths converted to the
+// same template, e.g. /a/:id and /a/{id}, are merged into one path item.
+func (pt *PathTrie) ToOpenAPI3(info OpenAPIInfo) ([]byte, error) {
+	if info.Title == "" || info.Version == "" {
+		return nil, fmt.Errorf("OpenAPI info title and version are required")
+	}
+
+	document := openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]openAPIPathItem),
+	}
+	for _, rootNode := range pt.Trie {
+		walkAll(rootNode, func(node *TrieNode) bool {
+			template, params := pt.openAPITemplate(node.FullPath)
+			operations := openAPIOperations(node.Value)
+			if pathItem, ok := document.Paths[template]; ok {
+				// The params only depend on the template, so they are the same.
+				for method, operation := range pathItem.Operations {
+					if operations == nil {
+						operations = make(map[string]openAPIOperation)
+					}
+					operations[method] = operation
+				}
+			}
+			document.Paths[template] = openAPIPathItem{
+				Parameters: params,
+				Operations: operations,
+			}
+			return true
+		})
+	}
+
+	data, err := yaml.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OpenAPI 3 document: %w", err)
+	}
+
+	return data, nil
+}
+
+// openAPITemplate converts fullPath to an OpenAPI path template and returns it
+// along with its path params. Param names are stripped of their delimiters,
+// e.g. `:id` becomes `{id}`, and made unique by numbering the duplicates.
+func (pt *PathTrie) openAPITemplate(fullPath string) (string, []openAPIParameter) {
+	segments := strings.Split(fullPath, pt.PathSeparator)
+	var params []openAPIParameter
+	names := make(map[string]int)
+	for idx, segment := range segments {
+		if !pt.isPathParam(segment) {
+			continue
+		}
+
+		name := openAPIParamName(segment)
+		names[name]++
+		if count := names[name]; count > 1 {
+			name = fmt.Sprintf("%s%d", name, count)
+		}
+		segments[idx] = "{" + name + "}"
+		params = append(params, openAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   map[string]string{"type": "string"},
+		})
+	}
+
+	template := strings.Join(segments, "/")
+	if !strings.HasPrefix(template, "/") {
+		template = "/" + template
+	}
+
+	return template, params
+}
+
+func openAPIParamName(segment string) string {
+	switch segment {
+	case Wildcard:
+		return "param"
+	case CatchAll:
+		return "path"
+	}
+
+	name := strings.Trim(segment, "{}:<>")
+	if name == "" {
+		return "param"
+	}
+
+	return name
+}
+
+func openAPIOperations(value any) map[string]openAPIOperation {
+	methods, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	operations := make(map[string]openAPIOperation)
+	for method := range methods {
+		method = strings.ToLower(method)
+		if !openAPIMethods[method] {
+			continue
+		}
+		operations[method] = openAPIOperation{
+			Responses: map[string]openAPIResponse{
+				"default": {Description: "Observed response"},
+			},
+		}
+	}
+
+	return operations
+}
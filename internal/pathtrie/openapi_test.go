@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+
+	"github.com/5gsec/api-speculator/internal/util"
+)
+
+func TestPathTrie_ToOpenAPI3(t *testing.T) {
+	pt := New()
+	pt.Insert("/v1/users", map[string]any{"GET": nil, "POST": nil})
+	pt.Insert("/v1/users/{id}", map[string]any{"GET": nil, "DELETE": nil, "CONNECT": nil})
+	pt.Insert("/v1/users/{id}/friends/{id}", "no methods")
+	pt.Insert("/v1/files/**", map[string]any{"GET": nil})
+
+	got, err := pt.ToOpenAPI3(OpenAPIInfo{Title: "Discovered API", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("ToOpenAPI3() error = %v", err)
+	}
+
+	want := `openapi: 3.0.3
+info:
+    title: Discovered API
+    version: 1.0.0
+paths:
+    /v1/files/{path}:
+        parameters:
+            - name: path
+              in: path
+              required: true
+              schema:
+                type: string
+        get:
+            responses:
+                default:
+                    description: Observed response
+    /v1/users:
+        get:
+            responses:
+                default:
+                    description: Observed response
+        post:
+            responses:
+                default:
+                    description: Observed response
+    /v1/users/{id}:
+        parameters:
+            - name: id
+              in: path
+              required: true
+              schema:
+                type: string
+        delete:
+            responses:
+                default:
+                    description: Observed response
+        get:
+            responses:
+                default:
+                    description: Observed response
+    /v1/users/{id}/friends/{id2}:
+        parameters:
+            - name: id
+              in: path
+              required: true
+              schema:
+                type: string
+            - name: id2
+              in: path
+              required: true
+              schema:
+                type: string
+`
+	if string(got) != want {
+		t.Errorf("ToOpenAPI3() = %v, want %v", string(got), want)
+	}
+
+	document, err := libopenapi.NewDocument(got)
+	if err != nil {
+		t.Fatalf("ToOpenAPI3() output is not parsable: %v", err)
+	}
+	if _, errs := document.BuildV3Model(); len(errs) > 0 {
+		t.Errorf("ToOpenAPI3() output is not a valid model: %v", errs)
+	}
+}
+
+func TestPathTrie_ToOpenAPI3_customParams(t *testing.T) {
+	pt := NewWithPathSeparator(".")
+	pt.IsPathParam = func(segment string) bool {
+		return len(segment) > 1 && segment[0] == ':'
+	}
+	pt.Insert("v1.users.:id", map[string]any{"get": nil})
+
+	got, err := pt.ToOpenAPI3(OpenAPIInfo{Title: "Discovered API", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("ToOpenAPI3() error = %v", err)
+	}
+
+	want := `openapi: 3.0.3
+info:
+    title: Discovered API
+    version: 1.0.0
+paths:
+    /v1/users/{id}:
+        parameters:
+            - name: id
+              in: path
+              required: true
+              schema:
+                type: string
+        get:
+            responses:
+                default:
+                    description: Observed response
+`
+	if string(got) != want {
+		t.Errorf("ToOpenAPI3() = %v, want %v", string(got), want)
+	}
+}
+
+func TestPathTrie_ToOpenAPI3_templateCollision(t *testing.T) {
+	pt := New()
+	pt.IsPathParam = func(segment string) bool {
+		return strings.HasPrefix(segment, ":") || util.IsPathParam(segment)
+	}
+	pt.Insert("/a/:id", map[string]any{"GET": nil})
+	pt.Insert("/a/{id}", map[string]any{"POST": nil})
+
+	got, err := pt.ToOpenAPI3(OpenAPIInfo{Title: "Discovered API", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("ToOpenAPI3() error = %v", err)
+	}
+
+	want := `openapi: 3.0.3
+info:
+    title: Discovered API
+    version: 1.0.0
+paths:
+    /a/{id}:
+        parameters:
+            - name: id
+              in: path
+              required: true
+              schema:
+                type: string
+        get:
+            responses:
+                default:
+                    description: Observed response
+        post:
+            responses:
+                default:
+                    description: Observed response
+`
+	if string(got) != want {
+		t.Errorf("ToOpenAPI3() = %v, want %v", string(got), want)
+	}
+}
+
+func TestPathTrie_ToOpenAPI3_missingInfo(t *testing.T) {
+	pt := New()
+	if _, err := pt.ToOpenAPI3(OpenAPIInfo{Title: "Discovered API"}); err == nil {
+		t.Errorf("ToOpenAPI3() error = nil, want error")
+	}
+}
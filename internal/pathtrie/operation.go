@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"strings"
+)
+
+// MatchOperation resolves path like GetValue and, assuming its value is a
+// map[string]any of operations keyed by uppercased HTTP method, returns the
+// operation of method and true. Otherwise false is returned along with:
+//   - nil if no path matches, i.e. the path is unknown;
+//   - ErrMethodNotAllowed itself, unwrapped, if the path matches but its value
+//     holds no operation for method, including when it isn't a map of
+//     operations.
+//
+// Callers tell an unknown path from a method not allowed by comparing the value
+// with ErrMethodNotAllowed when false is returned.
+func (pt *PathTrie) MatchOperation(method, path string) (any, bool) {
+	node := pt.getNode(path)
+	if node == nil {
+		return nil, false
+	}
+
+	operations, ok := node.Value.(map[string]any)
+	if !ok {
+		return ErrMethodNotAllowed, false
+	}
+	operation, ok := operations[strings.ToUpper(method)]
+	if !ok {
+		return ErrMethodNotAllowed, false
+	}
+
+	return operation, true
+}
+
+// InsertOperation inserts val at path under a leading segment holding the
+// uppercased method, so that GET /v1/users and POST /v1/users are distinct paths
+// rather than entries of a map value. The method segment takes the place of the
+// leading empty segment of absolute paths, e.g. the full path is GET/v1/users,
+// which is what GetChildren and Walk report. Returns false if an existing
+// operation was overwritten or if the path was skipped or rejected by the
+// insertion guards, see TryInsertMerge.
+func (pt *PathTrie) InsertOperation(method, path string, val any) bool {
+	// The guards apply to the path alone, the method segment doesn't count
+	// toward e.g. MaxDepth.
+	if err := pt.checkInsert(path, pt.splitPath(path)); err != nil {
+		return false
+	}
+	segments := pt.operationSegments(method, path)
+
+	tries := make([]PathToTrieNode, 1, len(segments)+1)
+	tries[0] = pt.Trie
+	isNewPath, _ := pt.insertSegments(tries, segments, val, func(existing, newV *any) {
+		*existing = *newV
+	})
+
+	return isNewPath
+}
+
+// GetOperation returns the value inserted by InsertOperation for method at the
+// node matching path. Path params only match the path segments, never the
+// method.
+func (pt *PathTrie) GetOperation(method, path string) (any, bool) {
+	if pt.PathSeparator == "" {
+		return nil, false
+	}
+
+	segments := pt.operationSegments(method, path)
+	// Only match under the method node, so that a relative path starting with a
+	// path param can't match the method segment.
+	key := pt.nodeKey(segments[0])
+	methodNode, ok := pt.Trie[key]
+	if !ok {
+		return nil, false
+	}
+	nodes := pt.getMatchesIn(PathToTrieNode{key: methodNode}, segments)
+	if pt.Metrics != nil {
+		pt.Metrics.IncMatch(len(nodes) > 0)
+	}
+	if len(nodes) == 0 {
+		return nil, false
+	}
+
+	return nodes[0].Value, true
+}
+
+// operationSegments returns the segments of path prefixed with the method
+// segment, see InsertOperation.
+func (pt *PathTrie) operationSegments(method, path string) []string {
+	method = strings.ToUpper(method)
+	segments := pt.splitPath(path)
+	if len(segments) > 0 && segments[0] == "" {
+		segments[0] = method
+		return segments
+	}
+
+	return append([]string{method}, segments...)
+}
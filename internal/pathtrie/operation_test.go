@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPathTrie_MatchOperation(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: map[string]any{"GET": "getUser", "DELETE": "deleteUser"}},
+		pathAndValue{path: "/v1/health", value: "not operations"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		method string
+		path   string
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   any
+		wantOk bool
+	}{
+		{
+			name: "allowed method",
+			args: args{
+				method: "GET",
+				path:   "/v1/users/42",
+			},
+			want:   "getUser",
+			wantOk: true,
+		},
+		{
+			name: "lowercase method",
+			args: args{
+				method: "delete",
+				path:   "/v1/users/42",
+			},
+			want:   "deleteUser",
+			wantOk: true,
+		},
+		{
+			name: "method not allowed",
+			args: args{
+				method: "POST",
+				path:   "/v1/users/42",
+			},
+			want:   ErrMethodNotAllowed,
+			wantOk: false,
+		},
+		{
+			name: "value without operations",
+			args: args{
+				method: "GET",
+				path:   "/v1/health",
+			},
+			want:   ErrMethodNotAllowed,
+			wantOk: false,
+		},
+		{
+			name: "unknown path",
+			args: args{
+				method: "GET",
+				path:   "/v1/orders",
+			},
+			want:   nil,
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := pt.MatchOperation(tt.args.method, tt.args.path)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchOperation() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("MatchOperation() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestPathTrie_MatchOperation_failures(t *testing.T) {
+	pt := New()
+	pt.Insert("/v1/users/{id}", map[string]any{"GET": "getUser"})
+
+	t.Run("unknown path", func(t *testing.T) {
+		op, ok := pt.MatchOperation("GET", "/v1/orders/7")
+		if ok || op != nil {
+			t.Errorf("MatchOperation() = %v, %v, want nil, false", op, ok)
+		}
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		op, ok := pt.MatchOperation("PUT", "/v1/users/42")
+		if ok || op != ErrMethodNotAllowed {
+			t.Errorf("MatchOperation() = %v, %v, want %v, false", op, ok, ErrMethodNotAllowed)
+		}
+	})
+}
+
+func TestPathTrie_InsertOperation(t *testing.T) {
+	pt := New()
+	for _, op := range []struct {
+		method string
+		path   string
+		val    any
+	}{
+		{method: "GET", path: "/v1/users", val: "listUsers"},
+		{method: "post", path: "/v1/users", val: "createUser"},
+		{method: "GET", path: "/v1/users/{id}", val: "getUser"},
+		{method: "GET", path: "v1/relative", val: "relative"},
+	} {
+		if !pt.InsertOperation(op.method, op.path, op.val) {
+			t.Fatalf("InsertOperation(%s, %s) = false, want true", op.method, op.path)
+		}
+	}
+	if pt.InsertOperation("GET", "/v1/users", "listAllUsers") {
+		t.Errorf("InsertOperation() = true, want false when overwriting")
+	}
+
+	type args struct {
+		method string
+		path   string
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   any
+		wantOk bool
+	}{
+		{
+			name: "method",
+			args: args{
+				method: "GET",
+				path:   "/v1/users",
+			},
+			want:   "listAllUsers",
+			wantOk: true,
+		},
+		{
+			name: "other method",
+			args: args{
+				method: "Post",
+				path:   "/v1/users",
+			},
+			want:   "createUser",
+			wantOk: true,
+		},
+		{
+			name: "path param",
+			args: args{
+				method: "GET",
+				path:   "/v1/users/42",
+			},
+			want:   "getUser",
+			wantOk: true,
+		},
+		{
+			name: "missing method",
+			args: args{
+				method: "DELETE",
+				path:   "/v1/users/42",
+			},
+			want:   nil,
+			wantOk: false,
+		},
+		{
+			name: "relative path",
+			args: args{
+				method: "GET",
+				path:   "v1/relative",
+			},
+			want:   "relative",
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := pt.GetOperation(tt.args.method, tt.args.path)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetOperation() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("GetOperation() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+
+	want := []string{"GET", "GET/v1", "GET/v1/relative", "GET/v1/users", "GET/v1/users/{id}", "POST", "POST/v1", "POST/v1/users"}
+	got := pt.GetChildren()
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetChildren() = %v, want %v", got, want)
+	}
+
+	// The method is matched literally, never by a path param.
+	pt.Insert("{id}/users", "param")
+	if got, ok := pt.GetOperation("GET", "/users"); ok {
+		t.Errorf("GetOperation() = %v, want the method segment not to match a path param", got)
+	}
+}
+
+func TestPathTrie_InsertOperation_MaxDepth(t *testing.T) {
+	pt := New()
+	pt.MaxDepth = 2
+
+	if !pt.Insert("/a/b", 1) {
+		t.Error("Insert() = false at MaxDepth, want true")
+	}
+	if !pt.InsertOperation("GET", "/a/b", 2) {
+		t.Error("InsertOperation() = false at MaxDepth, want true")
+	}
+	if pt.InsertOperation("GET", "/a/b/c", 3) {
+		t.Error("InsertOperation() = true beyond MaxDepth, want false")
+	}
+	if got, _ := pt.GetOperation("GET", "/a/b"); got != 2 {
+		t.Errorf("GetOperation() = %v, want 2", got)
+	}
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+// Option configures a PathTrie created by New.
+type Option func(pt *PathTrie)
+
+// WithSeparator sets the PathSeparator, "/" by default.
+func WithSeparator(pathSeparator string) Option {
+	return func(pt *PathTrie) {
+		pt.PathSeparator = pathSeparator
+	}
+}
+
+// WithCaseInsensitive sets CaseInsensitive.
+func WithCaseInsensitive() Option {
+	return func(pt *PathTrie) {
+		pt.CaseInsensitive = true
+	}
+}
+
+// WithTrimTrailingSeparator sets TrimTrailingSeparator.
+func WithTrimTrailingSeparator() Option {
+	return func(pt *PathTrie) {
+		pt.TrimTrailingSeparator = true
+	}
+}
+
+// WithMaxDepth sets MaxDepth.
+func WithMaxDepth(maxDepth int) Option {
+	return func(pt *PathTrie) {
+		pt.MaxDepth = maxDepth
+	}
+}
+
+// WithMaxPathParams sets MaxPathParams.
+func WithMaxPathParams(maxPathParams int) Option {
+	return func(pt *PathTrie) {
+		pt.MaxPathParams = maxPathParams
+	}
+}
+
+// WithParamDetector sets IsPathParam, e.g. to recognize :id segments as path
+// params.
+func WithParamDetector(isPathParam func(segment string) bool) Option {
+	return func(pt *PathTrie) {
+		pt.IsPathParam = isPathParam
+	}
+}
+
+// WithDecodeSegments sets DecodeSegments.
+func WithDecodeSegments() Option {
+	return func(pt *PathTrie) {
+		pt.DecodeSegments = true
+	}
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNew_options(t *testing.T) {
+	pt := New(
+		WithSeparator("."),
+		WithCaseInsensitive(),
+		WithTrimTrailingSeparator(),
+		WithMaxDepth(3),
+		WithMaxPathParams(1),
+		WithParamDetector(func(segment string) bool {
+			return strings.HasPrefix(segment, ":")
+		}),
+		WithDecodeSegments(),
+	)
+
+	if !pt.Insert("v1.Users.:id.", 1) {
+		t.Fatal("Insert() = false, want true")
+	}
+	if got := pt.GetValue("v1.users.42"); got != 1 {
+		t.Errorf("GetValue() = %v, want 1", got)
+	}
+	pt.Insert("v1.a%20b", 3)
+	if got := pt.GetValue("v1.a b"); got != 3 {
+		t.Errorf("GetValue() = %v, want 3", got)
+	}
+	if _, err := pt.TryInsertMerge("v1.users.:id.posts", 2, nil); !errors.Is(err, ErrMaxDepthExceeded) {
+		t.Errorf("TryInsertMerge() error = %v, want %v", err, ErrMaxDepthExceeded)
+	}
+	if _, err := pt.TryInsertMerge("v1.:a.:b", 2, nil); !errors.Is(err, ErrMaxParamsExceeded) {
+		t.Errorf("TryInsertMerge() error = %v, want %v", err, ErrMaxParamsExceeded)
+	}
+}
+
+func TestNew_defaults(t *testing.T) {
+	pt := New()
+	if pt.PathSeparator != "/" || pt.Trie == nil {
+		t.Errorf("New() = %v, want an empty trie separated by /", pt)
+	}
+	if got := NewWithPathSeparator("::"); got.PathSeparator != "::" {
+		t.Errorf("NewWithPathSeparator() PathSeparator = %v, want ::", got.PathSeparator)
+	}
+}
+
+func TestNew_emptySeparator(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("New() didn't panic")
+		}
+	}()
+	New(WithSeparator(""))
+}
@@ -4,11 +4,23 @@
 package pathtrie
 
 import (
+	"fmt"
+	"slices"
 	"strings"
+	"sync/atomic"
 
 	"github.com/5gsec/api-speculator/internal/util"
 )
 
+const (
+	// Wildcard is a path segment matching any single segment, like a path param.
+	Wildcard = "*"
+
+	// CatchAll is a path segment matching all the remaining segments, however many
+	// they are. It has the lowest matching priority.
+	CatchAll = "**"
+)
+
 type PathToTrieNode map[string]*TrieNode
 
 type TrieNode struct {
@@ -26,25 +38,172 @@ type TrieNode struct {
 	// PathParamCounter counts the number of path params in the FullPath.
 	PathParamCounter int
 
+	// SegmentCount counts the non-empty segments of the FullPath, e.g. 3 for
+	// /v1/users/{id}.
+	SegmentCount int
+
 	// Value of the full path.
 	Value any
+
+	// Hits counts the lookups of the full path recorded by Touch. It is updated
+	// atomically.
+	Hits uint64
+
+	// LastSeen is the time of the last lookup of the full path recorded by Touch,
+	// in Unix nanoseconds, or zero if there is none. It is an int64 rather than a
+	// time.Time so that Touch can update it atomically, SafePathTrie.Touch only
+	// taking the read lock. Use LastSeenTime to read it as a time.Time.
+	LastSeen int64
+
+	// ParamNames are the distinct original names of the path params unified into
+	// this node, in insertion order, see PathTrie.KeepParamNames.
+	ParamNames []string
+
+	// observedValues are the distinct segments mapped onto this path param node,
+	// see PathTrie.MaxObservedValues.
+	observedValues []string
 }
 
+// PathTrie stores values by path, matching path params segments against any
+// segment on lookup. It takes no lock and is meant for single-threaded use,
+// see SafePathTrie for concurrent access.
 type PathTrie struct {
 	Trie          PathToTrieNode
 	PathSeparator string
+
+	// IsPathParam reports whether a path segment is a path param, e.g. to support
+	// `:id` or `<id>` placeholders. Defaults to util.IsPathParam when nil.
+	IsPathParam func(segment string) bool
+
+	// CaseInsensitive makes static segments match regardless of their case. Nodes
+	// keep the casing of the path that first created them, which is the one
+	// reported by GetChildren and FullPath.
+	CaseInsensitive bool
+
+	// TrimTrailingSeparator strips a single trailing separator from paths, so that
+	// /v1/foo and /v1/foo/ map to the same node. The bare separator is left as is.
+	TrimTrailingSeparator bool
+
+	// CollapseEmptySegments drops the empty segments produced by consecutive
+	// separators, so that /v1//foo and /v1/foo map to the same node.
+	CollapseEmptySegments bool
+
+	// StripQueryAndFragment trims everything from the first '?' or '#' of paths,
+	// so that raw request targets such as /v1/foo?bar=1 map to /v1/foo. Those
+	// that are part of the separator are ignored.
+	StripQueryAndFragment bool
+
+	// DecodeSegments percent-decodes each segment after splitting, so that
+	// /files/a%20b and /files/a b map to the same node. Encoded separators, e.g.
+	// %2F or %3A%3A for ::, are kept encoded and never split the segment, see
+	// util.NormalizeSegment.
+	DecodeSegments bool
+
+	// EscapeByte, if not zero, escapes the separator it precedes, so that e.g.
+	// a\/b is a single segment with a backslash escape byte. Escaped separators
+	// are stored percent-encoded, as a%2Fb, which keeps FullPath unambiguous.
+	// Hence an escaped separator and its percent-encoded form map to the same
+	// segment, including with DecodeSegments, which keeps %2F encoded. An escape
+	// byte not followed by the separator is kept as is. Escaping is disabled for
+	// the separators whose percent-encoded form contains them, e.g. 2 or %.
+	EscapeByte byte
+
+	// NormalizeUnicode applies Unicode NFC normalization to each segment, after
+	// DecodeSegments if set, so that a precomposed caf\u00e9 and a decomposed
+	// cafe\u0301 map to the same node.
+	NormalizeUnicode bool
+
+	// OnOverwrite, if set, is called by InsertMerge with the FullPath of the node
+	// and its old value whenever a path already holding a value is inserted again,
+	// before the merge function updates the value.
+	OnOverwrite func(path string, old, newV any)
+
+	// WeightedTieBreak makes lookups prefer, among the matching nodes with the
+	// same number of path params, the one with the most Hits, see Touch.
+	WeightedTieBreak bool
+
+	// OnParamStaticCollision, if set, is called by InsertMerge whenever a static
+	// node is added next to a path param sibling, or a path param node next to
+	// static siblings, e.g. /v1/users/me next to /v1/users/{id}, once per
+	// sibling. Such paths usually stem from a spec bug, as one of them shadows
+	// the other. It doesn't change the matching.
+	OnParamStaticCollision func(paramPath, staticPath string)
+
+	// MaxObservedValues, if positive, makes InsertMerge map a segment matching no
+	// node onto an existing path param sibling, e.g. /v1/users/42 onto
+	// /v1/users/{id}, rather than creating a node. Up to MaxObservedValues
+	// distinct mapped segments are recorded, see TrieNode.ObservedValues. CatchAll
+	// siblings are ignored and the sibling with the lowest name is used if there
+	// are several.
+	MaxObservedValues int
+
+	// StrictMatch makes lookups fail closed, finding no node rather than picking
+	// one when the most accurate matching nodes are tied, see GetValueStrict.
+	StrictMatch bool
+
+	// UnifyPathParams stores every path param segment, whatever its name, under a
+	// single node named CompactedParam, or Wildcard if IsPathParam doesn't
+	// recognize it, so that e.g. /v1/users/{userId} and /v1/users/{id} are the
+	// same route. Wildcard and CatchAll segments keep their meaning.
+	UnifyPathParams bool
+
+	// KeepParamNames records the original names of the unified path params in
+	// the ParamNames of their node, for reporting.
+	KeepParamNames bool
+
+	// MaxPathParams, if positive, is the maximum number of path params of the
+	// inserted paths, as a safety valve against parameterization bugs turning
+	// every segment into a param. See TryInsertMerge.
+	MaxPathParams int
+
+	// MaxDepth, if positive, is the maximum number of segments of the inserted
+	// paths, not counting the root of absolute paths, to refuse malformed paths
+	// of pathological depth. See TryInsertMerge.
+	MaxDepth int
+
+	// RejectEmptySegments makes insertions of paths with empty segments, e.g.
+	// /v1//foo, fail unless CollapseEmptySegments drops them. The root of
+	// absolute paths and the trailing separator are allowed. See TryInsertMerge.
+	RejectEmptySegments bool
+
+	// IncludePrefixes, if not empty, restricts the inserted paths to those
+	// starting with one of these prefixes, e.g. /v1. ExcludePrefixes skips the
+	// inserted paths starting with one of these prefixes, e.g. /healthz or
+	// /metrics, even if included. Prefixes are matched segment by segment, so
+	// /healthz doesn't match /healthzz, and their path params match any segment.
+	// See TryInsertMerge.
+	IncludePrefixes []string
+	ExcludePrefixes []string
+
+	// MaxCandidates, if positive, bounds the number of matching nodes collected
+	// by a lookup, so that lookups matching many param branches don't spike the
+	// memory usage. The most accurate of the collected nodes is returned, which
+	// may not be the most accurate of all, and Metrics is notified.
+	MaxCandidates int
+
+	// Metrics, if set, is notified of lookups, insertions and node count
+	// changes.
+	Metrics Metrics
+
+	// nodeCount is the node count last reported to Metrics, valid only if
+	// nodeCountValid is set.
+	nodeCount      int
+	nodeCountValid bool
+
+	// freeNodes are the nodes detached by Reset, with their children map
+	// cleared, reused by createPathTrieNode.
+	freeNodes []*TrieNode
 }
 
 type ValueMergeFunc func(existing, newV *any)
 
 func (pt *PathTrie) createPathTrieNode(segments []string, idx int, isLastSegment bool, val any) *TrieNode {
 	fullPathSegments := segments[:idx+1]
-	node := &TrieNode{
-		Children: make(PathToTrieNode),
-		Name:     segments[idx],
-		FullPath: strings.Join(fullPathSegments, pt.PathSeparator),
-	}
-	node.PathParamCounter = countPathParam(fullPathSegments)
+	node := pt.newNode()
+	node.Name = segments[idx]
+	node.FullPath = strings.Join(fullPathSegments, pt.PathSeparator)
+	node.PathParamCounter = pt.countPathParam(fullPathSegments)
+	node.SegmentCount = countSegments(fullPathSegments)
 	if isLastSegment {
 		node.Value = val
 	}
@@ -52,11 +211,39 @@ func (pt *PathTrie) createPathTrieNode(segments []string, idx int, isLastSegment
 	return node
 }
 
-func countPathParam(segments []string) int {
+// newNode returns an empty node, reusing one detached by Reset if any.
+func (pt *PathTrie) newNode() *TrieNode {
+	last := len(pt.freeNodes) - 1
+	if last < 0 {
+		return &TrieNode{Children: make(PathToTrieNode)}
+	}
+
+	node := pt.freeNodes[last]
+	pt.freeNodes = pt.freeNodes[:last]
+	if node.Children == nil {
+		node.Children = make(PathToTrieNode)
+	}
+	return node
+}
+
+func (pt *PathTrie) countPathParam(segments []string) int {
+	count := 0
+
+	for _, segment := range segments {
+		if pt.isPathParam(segment) {
+			count += 1
+		}
+	}
+
+	return count
+}
+
+// countSegments returns the number of non-empty segments.
+func countSegments(segments []string) int {
 	count := 0
 
 	for _, segment := range segments {
-		if util.IsPathParam(segment) {
+		if segment != "" {
 			count += 1
 		}
 	}
@@ -64,46 +251,218 @@ func countPathParam(segments []string) int {
 	return count
 }
 
+// ParamPositions returns the indices of the path param segments of the FullPath
+// of the node matching path, as split on the separator, e.g. [3] for
+// /v1/users/{id} as its leading segment is empty. Their number is the
+// PathParamCounter of the node. Returns nil if no node matches path.
+func (pt *PathTrie) ParamPositions(path string) []int {
+	node := pt.getNode(path)
+	if node == nil {
+		return nil
+	}
+
+	positions := make([]int, 0, node.PathParamCounter)
+	for idx, segment := range strings.Split(node.FullPath, pt.PathSeparator) {
+		if pt.isPathParam(segment) {
+			positions = append(positions, idx)
+		}
+	}
+
+	return positions
+}
+
+// MatchSpecificity returns the share of static segments in the FullPath of the
+// node, from 1 for a fully literal path to 0 for path params only, e.g. 0.5 for
+// /v1/{id}. It is derived from PathParamCounter and SegmentCount, and a path
+// without any non-empty segment is fully literal.
+func (node *TrieNode) MatchSpecificity() float64 {
+	if node.SegmentCount == 0 {
+		return 1
+	}
+
+	return float64(node.SegmentCount-node.PathParamCounter) / float64(node.SegmentCount)
+}
+
+// IsParamSegment reports whether segment is a path param as the PathTrie sees
+// it, i.e. a Wildcard or CatchAll segment, or a segment recognized by
+// IsPathParam, util.IsPathParam if nil.
+func (pt *PathTrie) IsParamSegment(segment string) bool {
+	return pt.isPathParam(segment)
+}
+
+// isPathParam reports whether segment is a path param, using the IsPathParam
+// predicate if set. The Wildcard and CatchAll segments are always considered
+// path params.
+func (pt *PathTrie) isPathParam(segment string) bool {
+	if segment == Wildcard || segment == CatchAll {
+		return true
+	}
+
+	if pt.IsPathParam != nil {
+		return pt.IsPathParam(segment)
+	}
+
+	return util.IsPathParam(segment)
+}
+
+// nodeKey returns the key of segment in a PathToTrieNode.
+func (pt *PathTrie) nodeKey(segment string) string {
+	segment = pt.unifyParam(segment)
+	if pt.CaseInsensitive && !pt.isPathParam(segment) {
+		return foldCase(segment)
+	}
+
+	return segment
+}
+
 // InsertMerge takes a merge function which is responsible for updating the
-// existing value with the new value.
+// existing value with the new value. Paths rejected by the insertion guards,
+// see TryInsertMerge, are ignored and false is returned.
 func (pt *PathTrie) InsertMerge(path string, val any, merge ValueMergeFunc) (isNewPath bool) {
-	trie := pt.Trie
-	isNewPath = true
-	// TODO: what about path that ends with pt.PathSeparator is it different ?
-	segments := strings.Split(path, pt.PathSeparator)
+	isNewPath, _ = pt.TryInsertMerge(path, val, merge)
+	return isNewPath
+}
+
+// TryInsertMerge is like InsertMerge but returns an *InsertError if the path is
+// rejected by a guard such as MaxPathParams, MaxDepth or RejectEmptySegments.
+// The whole path is validated before the trie is mutated, so a rejected path
+// never leaves intermediate nodes behind. A path skipped because of
+// IncludePrefixes or ExcludePrefixes returns an error wrapping ErrPathFiltered.
+func (pt *PathTrie) TryInsertMerge(path string, val any, merge ValueMergeFunc) (bool, error) {
+	// A path ending with pt.PathSeparator is different unless
+	// TrimTrailingSeparator is set.
+	segments := pt.splitPath(path)
+	if err := pt.checkInsert(path, segments); err != nil {
+		return false, err
+	}
+
+	tries := make([]PathToTrieNode, 1, len(segments)+1)
+	tries[0] = pt.Trie
+	isNewPath, _ := pt.insertSegments(tries, segments, val, merge)
+
+	return isNewPath, nil
+}
+
+// checkInsert returns the error of TryInsertMerge if the segments of path are
+// skipped by IncludePrefixes or ExcludePrefixes, or rejected by the insertion
+// guards.
+func (pt *PathTrie) checkInsert(path string, segments []string) error {
+	if pt.isFiltered(segments) {
+		return fmt.Errorf("cannot insert path `%s`: %w", path, ErrPathFiltered)
+	}
+
+	return pt.validatePath(path, segments)
+}
+
+// insertSegments inserts val at segments, starting the descent at the last of
+// tries, which holds the node of segments[len(tries)-1]. The children map of
+// each node on the way is appended to tries, which is returned.
+func (pt *PathTrie) insertSegments(tries []PathToTrieNode, segments []string, val any, merge ValueMergeFunc) (bool, []PathToTrieNode) {
+	trie := tries[len(tries)-1]
+	isNewPath := true
+	created := 0
 
 	// Traverse the Trie along path, inserting nodes where necessary.
-	for idx, segment := range segments {
+	for idx := len(tries) - 1; idx < len(segments); idx++ {
 		isLastSegment := idx == len(segments)-1
-		if node, ok := trie[segment]; ok {
+		name := segments[idx]
+		segments[idx] = pt.unifyParam(name)
+		key := pt.nodeKey(segments[idx])
+		node, ok := trie[key]
+		if !ok && pt.MaxObservedValues > 0 {
+			// Map the segment onto a path param sibling, if any.
+			if node = pt.observeParam(trie, segments[idx]); node != nil {
+				ok = true
+				key = pt.nodeKey(node.Name)
+			}
+		}
+		if ok && pt.isCompressed(node) {
+			if next, match := pt.matchNode(node, segments, idx); match && next < len(segments) {
+				// Descend past the compressed node, which doesn't hold the children maps
+				// of its inner segments.
+				copy(segments[idx:next], strings.Split(node.Name, pt.PathSeparator))
+				for ; idx < next-1; idx++ {
+					tries = append(tries, nil)
+				}
+				trie = node.Children
+				tries = append(tries, trie)
+				continue
+			}
+			// Otherwise, path ends in or diverges from the compressed node.
+			node = pt.expand(trie, key)
+		}
+		if ok {
+			// Keep the existing node name, which may be cased differently, so that
+			// the FullPath of the nodes created below it is consistent.
+			segments[idx] = node.Name
 			if isLastSegment {
 				// If this is the last path segment, then this is the node to update.
 				// If node value is not empty it means that an existing path is overwritten.
 				isNewPath = util.IsNil(node.Value)
+				if !isNewPath && pt.OnOverwrite != nil {
+					pt.OnOverwrite(node.FullPath, node.Value, val)
+				}
 				merge(&node.Value, &val)
-			} else {
-				// Otherwise, continue descending.
-				trie = node.Children
 			}
+			pt.keepParamName(node, name)
+			// Continue descending.
+			trie = node.Children
 		} else {
 			newNode := pt.createPathTrieNode(segments, idx, isLastSegment, val)
-			trie[segment] = newNode
+			pt.keepParamName(newNode, name)
+			pt.reportCollisions(trie, newNode)
+			trie[key] = newNode
 			trie = newNode.Children
+			created++
 		}
+		tries = append(tries, trie)
 	}
 
-	return isNewPath
+	if pt.Metrics != nil {
+		pt.Metrics.IncInsert(isNewPath)
+		if created > 0 {
+			pt.addNodes(created)
+		}
+	}
+
+	return isNewPath, tries
 }
 
 // Insert inserts val at path, with path segments separated by PathSeparator.
 // Returns true if a new path was created, false if an existing path was
-// overwritten.
+// overwritten. The empty path and the bare separator are both the root path,
+// whose FullPath is the separator, e.g. for a root health endpoint.
 func (pt *PathTrie) Insert(path string, val any) bool {
 	return pt.InsertMerge(path, val, func(existing, newV *any) {
 		*existing = *newV
 	})
 }
 
+// InsertSegments is like Insert but takes the segments of the path, e.g. the
+// template of a spec path, as GetValueSegments does. Node names are the raw
+// segments, except that the separators they hold are percent-encoded as with
+// EscapeByte, so that a/b stays a single segment. Returns false if the path is
+// skipped or rejected by a guard, see TryInsertMerge, or holds a separator that
+// can't be encoded.
+func (pt *PathTrie) InsertSegments(segments []string, val any) bool {
+	encoded, ok := pt.encodeSeparators(segments)
+	if !ok || len(encoded) == 0 {
+		return false
+	}
+	encoded = slices.Clone(encoded)
+	if err := pt.checkInsert(strings.Join(encoded, pt.PathSeparator), encoded); err != nil {
+		return false
+	}
+
+	tries := make([]PathToTrieNode, 1, len(encoded)+1)
+	tries[0] = pt.Trie
+	isNewPath, _ := pt.insertSegments(tries, encoded, val, func(existing, newV *any) {
+		*existing = *newV
+	})
+
+	return isNewPath
+}
+
 // GetValue returns the given node path value, nil if node is not found.
 func (pt *PathTrie) GetValue(path string) any {
 	node := pt.getNode(path)
@@ -114,6 +473,28 @@ func (pt *PathTrie) GetValue(path string) any {
 	return node.Value
 }
 
+// GetValueOK returns the given node path value, and false only if no node
+// matched. Note that inserting an untyped nil value doesn't make a path match,
+// whereas a nil pointer value does.
+func (pt *PathTrie) GetValueOK(path string) (any, bool) {
+	node := pt.getNode(path)
+	if node == nil {
+		return nil, false
+	}
+
+	return node.Value, true
+}
+
+// GetValueOrDefault is like GetValue but returns def if no node matched.
+func (pt *PathTrie) GetValueOrDefault(path string, def any) any {
+	node := pt.getNode(path)
+	if node == nil {
+		return def
+	}
+
+	return node.Value
+}
+
 // GetPathAndValue returns the given node full path and value, nil if node is not found.
 func (pt *PathTrie) GetPathAndValue(path string) (string, any, bool) {
 	node := pt.getNode(path)
@@ -124,60 +505,132 @@ func (pt *PathTrie) GetPathAndValue(path string) (string, any, bool) {
 	return node.FullPath, node.Value, true
 }
 
-func (pt *PathTrie) getNode(path string) *TrieNode {
-	segments := strings.Split(path, pt.PathSeparator)
+// GetMatches returns every value-holding node matching path, sorted from the
+// most to the least accurate: an exact match first, then the nodes with fewest
+// path params segments. The first node is the one used by lookups such as
+// GetValue.
+func (pt *PathTrie) GetMatches(path string) []*TrieNode {
+	return pt.getMatches(pt.splitPath(path))
+}
 
-	nodes := pt.Trie.getMatchNodes(segments, 0)
+func (pt *PathTrie) getMatches(segments []string) []*TrieNode {
+	return pt.getMatchesIn(pt.Trie, segments)
+}
 
-	if len(nodes) == 0 {
-		return nil
+// getMatchesIn is like getMatches but only matches the nodes of trie.
+func (pt *PathTrie) getMatchesIn(trie PathToTrieNode, segments []string) []*TrieNode {
+	return pt.getMatchesInContext(trie, segments, pt.newMatchContext(nil))
+}
+
+// getMatchesInContext is like getMatchesIn but descends the trie with mc, see
+// GetValueContext.
+func (pt *PathTrie) getMatchesInContext(trie PathToTrieNode, segments []string, mc *matchContext) []*TrieNode {
+	nodes := pt.getMatchNodesFunc(trie, segments, 0, hasValue, mc)
+	pt.reportTruncation(mc)
+	slices.SortFunc(nodes, func(a, b *TrieNode) int {
+		if aExact, bExact := pt.isFullPathSegments(a.FullPath, segments), pt.isFullPathSegments(b.FullPath, segments); aExact != bExact {
+			if aExact {
+				return -1
+			}
+			return 1
+		}
+		return pt.compareAccuracy(a, b)
+	})
+
+	return nodes
+}
+
+// GetValueSegments is like GetValueOK but takes the segments of the path, as
+// split on the separator, saving the split when the caller already holds them,
+// e.g. [ v1 users 42] for /v1/users/42. No normalization option is applied to
+// segments, which aren't modified, besides the encoding of the separators they
+// hold, see InsertSegments.
+func (pt *PathTrie) GetValueSegments(segments []string) (any, bool) {
+	segments, ok := pt.encodeSeparators(segments)
+	if !ok {
+		return nil, false
 	}
 
-	if len(nodes) == 1 {
-		return nodes[0]
+	node := pt.getNodeSegments(segments)
+	if node == nil {
+		return nil, false
 	}
 
-	// if multiple nodes found, return the node with less path params segments
-	return getMostAccurateNode(nodes, path, len(segments))
+	return node.Value, true
 }
 
-// GetChildren returns a slice of full paths of each node present in the
-// PathTrie, that represents a complete path (i.e., has a no-empty FullPath).
-func (pt *PathTrie) GetChildren() []string {
-	var children []string
-	if pt.Trie == nil {
-		return children
+func (pt *PathTrie) getNode(path string) *TrieNode {
+	// An empty separator would split path into single characters.
+	if pt.PathSeparator == "" {
+		return nil
+	}
+
+	return pt.getNodeSegments(pt.splitPath(path))
+}
+
+func (pt *PathTrie) getNodeSegments(segments []string) *TrieNode {
+	return pt.getNodeSegmentsContext(segments, pt.newMatchContext(nil))
+}
+
+// getNodeSegmentsContext is like getNodeSegments but descends the trie with mc,
+// and returns nil without notifying Metrics if mc hits an error.
+func (pt *PathTrie) getNodeSegmentsContext(segments []string, mc *matchContext) *TrieNode {
+	nodes := pt.getMatchesInContext(pt.Trie, segments, mc)
+	if mc != nil && mc.err != nil {
+		return nil
 	}
-	for _, rootNode := range pt.Trie {
-		pt.getChildren(rootNode, &children)
+	if pt.StrictMatch && pt.isAmbiguous(nodes, segments) {
+		nodes = nil
 	}
-	return children
+	if pt.Metrics != nil {
+		pt.Metrics.IncMatch(len(nodes) > 0)
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	return nodes[0]
 }
 
-func (pt *PathTrie) getChildren(node *TrieNode, children *[]string) {
+// MatchDetail resolves path like GetValue and also reports whether the node
+// matched it exactly, i.e. its FullPath is the path itself with no path param
+// substitution, e.g. to label traffic as matching a literal rather than a
+// templated path. Note that a CaseInsensitive match with different casing isn't
+// exact.
+func (pt *PathTrie) MatchDetail(path string) (node *TrieNode, exact bool, ok bool) {
+	node = pt.getNode(path)
 	if node == nil {
-		return
+		return nil, false, false
 	}
 
-	// If the node has a FullPath, it represents a complete path, so add it to the
-	// list.
-	if node.FullPath != "" {
-		*children = append(*children, node.FullPath)
-	}
+	return node, node.isFullPathMatch(strings.Join(pt.splitPath(path), pt.PathSeparator)), true
+}
 
-	// We only recurse on child nodes with non-empty names to avoid processing the
-	// empty keys used for marking the end of a path within a parent's children map.
-	for childName, childNode := range node.Children {
-		if childName != "" {
-			pt.getChildren(childNode, children)
-		}
+// GetChildren returns a slice of full paths of each node present in the
+// PathTrie, that represents a complete path (i.e., has a no-empty FullPath).
+// The root path and the paths ending with a separator, held by empty-name
+// marker children, are listed as by Walk. The value-holding paths are the
+// ones yielded by All, as both go through the same traversal, but GetChildren
+// also lists the intermediate nodes, e.g. /v1 for /v1/users.
+func (pt *PathTrie) GetChildren() []string {
+	return pt.GetChildrenToDepth(0)
+}
+
+// GetChildrenToDepth is like GetChildren but only returns the nodes up to
+// maxDepth separator-delimited segments deep, path params segments counting as
+// much as static ones. A maxDepth of 0 means unlimited.
+func (pt *PathTrie) GetChildrenToDepth(maxDepth int) []string {
+	var children []string
+	for node := range pt.nodes(maxDepth) {
+		children = append(children, node.FullPath)
 	}
+	return children
 }
 
-// getMostAccurateNode returns the node with less path params segments.
-func getMostAccurateNode(nodes []*TrieNode, path string, segmentsLen int) *TrieNode {
+// getMostAccurateNode returns the node matching path exactly if any, otherwise
+// the most accurate node according to compareAccuracy.
+func (pt *PathTrie) getMostAccurateNode(nodes []*TrieNode, path string) *TrieNode {
 	var retNode *TrieNode
-	minPathParamSegmentsCount := segmentsLen + 1
 
 	for _, node := range nodes {
 		if node.isFullPathMatch(path) {
@@ -185,10 +638,8 @@ func getMostAccurateNode(nodes []*TrieNode, path string, segmentsLen int) *TrieN
 			return node
 		}
 
-		// TODO: if node.PathParamCounter == minPathParamSegmentsCount
-		if node.PathParamCounter < minPathParamSegmentsCount {
+		if retNode == nil || pt.compareAccuracy(node, retNode) < 0 {
 			// found more accurate node
-			minPathParamSegmentsCount = node.PathParamCounter
 			retNode = node
 		}
 	}
@@ -196,60 +647,187 @@ func getMostAccurateNode(nodes []*TrieNode, path string, segmentsLen int) *TrieN
 	return retNode
 }
 
-func (trie PathToTrieNode) getMatchNodes(segments []string, idx int) []*TrieNode {
-	var nodes []*TrieNode
+// compareAccuracy returns a negative number if a is more accurate than b, a
+// positive number if b is more accurate than a, and zero if they are the same
+// node. A CatchAll node is always the least accurate, otherwise the node with
+// less path params segments is the most accurate. On a tie, the node with the
+// most Hits wins if WeightedTieBreak is set, then the node whose first path
+// param segment occurs deepest (i.e. with the longest static prefix), and if
+// still tied the FullPaths are compared lexicographically so that the result
+// doesn't depend on map iteration order.
+func (pt *PathTrie) compareAccuracy(a, b *TrieNode) int {
+	if aCatchAll, bCatchAll := a.isCatchAll(), b.isCatchAll(); aCatchAll != bCatchAll {
+		if aCatchAll {
+			return 1
+		}
+		return -1
+	}
 
-	isLastSegment := idx == len(segments)-1
+	if a.PathParamCounter != b.PathParamCounter {
+		return a.PathParamCounter - b.PathParamCounter
+	}
+
+	if pt.WeightedTieBreak {
+		if aHits, bHits := atomic.LoadUint64(&a.Hits), atomic.LoadUint64(&b.Hits); aHits != bHits {
+			if aHits > bHits {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	if aIdx, bIdx := pt.firstPathParamIdx(a), pt.firstPathParamIdx(b); aIdx != bIdx {
+		return bIdx - aIdx
+	}
+
+	return strings.Compare(a.FullPath, b.FullPath)
+}
+
+// firstPathParamIdx returns the index of the first path param segment in the
+// node FullPath, or the number of segments if there is none.
+func (pt *PathTrie) firstPathParamIdx(node *TrieNode) int {
+	// Cut rather than split the FullPath, as it is called by every comparison.
+	path := node.FullPath
+	for idx := 0; ; idx++ {
+		segment, rest, found := strings.Cut(path, pt.PathSeparator)
+		if pt.isPathParam(segment) {
+			return idx
+		}
+		if !found {
+			return idx + 1
+		}
+		path = rest
+	}
+}
+
+func hasValue(node *TrieNode) bool {
+	return node.Value != nil
+}
+
+// getMatchNodesFunc returns the nodes matching segments for which accept returns
+// true on the last path segment. The descent stops early once mc, if not nil,
+// is done.
+func (pt *PathTrie) getMatchNodesFunc(trie PathToTrieNode, segments []string, idx int, accept func(*TrieNode) bool,
+	mc *matchContext) []*TrieNode {
+	return pt.appendMatchNodes(nil, trie, segments, idx, accept, pt.matchNode, mc)
+}
 
+// appendMatchNodes is like getMatchNodesFunc but appends the nodes to nodes,
+// which is shared by the whole descent so that it doesn't allocate per level,
+// and matches each node with match, e.g. matchNodePrefix for prefix lookups.
+func (pt *PathTrie) appendMatchNodes(nodes []*TrieNode, trie PathToTrieNode, segments []string, idx int,
+	accept func(*TrieNode) bool, match func(*TrieNode, []string, int) (int, bool), mc *matchContext) []*TrieNode {
 	for _, node := range trie {
+		if mc.done() {
+			break
+		}
+
 		// Check for node segment match
-		if !node.isNameMatch(segments[idx]) {
+		next, ok := match(node, segments, idx)
+		if !ok {
 			continue
 		}
 
-		// If this is the last path segment, then return node if it holds a value.
-		if isLastSegment {
-			if node.Value != nil {
+		// If this is the last path segment, or the node consumes all the remaining
+		// segments, then return node if accepted.
+		if next == len(segments) || node.isCatchAll() {
+			if accept(node) {
 				nodes = append(nodes, node)
+				mc.addCandidate()
 			}
 			continue
 		}
 
 		// Otherwise, continue descending.
-		newNodes := node.Children.getMatchNodes(segments, idx+1)
-		if len(newNodes) > 0 {
-			nodes = append(nodes, newNodes...)
-		}
+		nodes = pt.appendMatchNodes(nodes, node.Children, segments, next, accept, match, mc)
 	}
 
 	return nodes
 }
 
-func (node *TrieNode) isNameMatch(segment string) bool {
-	if util.IsPathParam(node.Name) {
+func (pt *PathTrie) isNameMatch(node *TrieNode, segment string) bool {
+	return pt.isSegmentMatch(node.Name, segment)
+}
+
+// isSegmentMatch reports whether a node named name matches segment.
+func (pt *PathTrie) isSegmentMatch(name, segment string) bool {
+	if pt.isPathParam(name) {
 		return true
 	}
 
-	if node.Name == segment {
+	if name == segment {
+		return true
+	}
+
+	if pt.CaseInsensitive && foldCase(name) == foldCase(segment) {
 		return true
 	}
 
 	return false
 }
 
+func (node *TrieNode) isCatchAll() bool {
+	return node.Name == CatchAll
+}
+
 func (node *TrieNode) isFullPathMatch(path string) bool {
 	return node.FullPath == path
 }
 
+// isFullPathSegments reports whether fullPath is segments joined by the
+// separator, without joining them.
+func (pt *PathTrie) isFullPathSegments(fullPath string, segments []string) bool {
+	for idx, segment := range segments {
+		if idx > 0 {
+			if !strings.HasPrefix(fullPath, pt.PathSeparator) {
+				return false
+			}
+			fullPath = fullPath[len(pt.PathSeparator):]
+		}
+		if !strings.HasPrefix(fullPath, segment) {
+			return false
+		}
+		fullPath = fullPath[len(segment):]
+	}
+
+	return fullPath == ""
+}
+
 // NewWithPathSeparator creates a PathTrie with a user-supplied path separator.
+// It panics if pathSeparator is empty, see NewWithPathSeparatorChecked.
 func NewWithPathSeparator(pathSeparator string) PathTrie {
-	return PathTrie{
-		Trie:          make(PathToTrieNode),
-		PathSeparator: pathSeparator,
+	return New(WithSeparator(pathSeparator))
+}
+
+// NewWithPathSeparatorChecked is like NewWithPathSeparator but returns
+// ErrEmptySeparator instead of panicking if pathSeparator is empty, e.g. when
+// it comes from the configuration.
+func NewWithPathSeparatorChecked(pathSeparator string) (PathTrie, error) {
+	return newChecked(WithSeparator(pathSeparator))
+}
+
+// New creates a PathTrie with "/" as the path separator, configured with opts.
+// It panics if an option sets an empty separator.
+func New(opts ...Option) PathTrie {
+	pt, err := newChecked(opts...)
+	if err != nil {
+		panic(err)
 	}
+
+	return pt
 }
 
-// New creates a PathTrie with "/" as the path separator.
-func New() PathTrie {
-	return NewWithPathSeparator("/")
+func newChecked(opts ...Option) (PathTrie, error) {
+	pt := PathTrie{
+		Trie:          make(PathToTrieNode),
+		PathSeparator: "/",
+	}
+	for _, opt := range opts {
+		opt(&pt)
+	}
+	if pt.PathSeparator == "" {
+		return PathTrie{}, fmt.Errorf("cannot create PathTrie: %w", ErrEmptySeparator)
+	}
+
+	return pt, nil
 }
@@ -5,11 +5,16 @@ package pathtrie
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/5gsec/api-speculator/internal/util"
 )
 
 type pathAndValue struct {
@@ -47,6 +52,7 @@ func TestPathTrie_getNode(t *testing.T) {
 				Name:             "items",
 				FullPath:         "/api/{param1}/items",
 				PathParamCounter: 1,
+				SegmentCount:     3,
 				Value:            1,
 			},
 		},
@@ -60,6 +66,7 @@ func TestPathTrie_getNode(t *testing.T) {
 				Name:             "items",
 				FullPath:         "/api/{param1}/items",
 				PathParamCounter: 1,
+				SegmentCount:     3,
 				Value:            1,
 			},
 		},
@@ -75,12 +82,14 @@ func TestPathTrie_getNode(t *testing.T) {
 						Name:             "cat",
 						FullPath:         "/api/items/cat",
 						PathParamCounter: 0,
+						SegmentCount:     3,
 						Value:            5,
 					},
 				},
 				Name:             "items",
 				FullPath:         "/api/items",
 				PathParamCounter: 0,
+				SegmentCount:     2,
 				Value:            2,
 			},
 		},
@@ -94,6 +103,7 @@ func TestPathTrie_getNode(t *testing.T) {
 				Name:             "{param2}",
 				FullPath:         "/api/{param1}/{param2}",
 				PathParamCounter: 2,
+				SegmentCount:     3,
 				Value:            3,
 			},
 		},
@@ -107,6 +117,7 @@ func TestPathTrie_getNode(t *testing.T) {
 				Name:             "cat",
 				FullPath:         "/api/{param1}/cat",
 				PathParamCounter: 1,
+				SegmentCount:     3,
 				Value:            4,
 			},
 		},
@@ -120,6 +131,7 @@ func TestPathTrie_getNode(t *testing.T) {
 				Name:             "cat",
 				FullPath:         "/api/items/cat",
 				PathParamCounter: 0,
+				SegmentCount:     3,
 				Value:            5,
 			},
 		},
@@ -140,6 +152,340 @@ func TestPathTrie_getNode(t *testing.T) {
 	}
 }
 
+func TestPathTrie_getNode_tieBreak(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/a/{x}/c", value: 1},
+		pathAndValue{path: "/a/b/{y}", value: 2},
+	); err != nil {
+		t.Error(err)
+	}
+
+	// Repeat the lookup since map iteration order varies between runs.
+	for i := 0; i < 100; i++ {
+		if got := pt.getNode("/a/b/c"); got == nil || got.FullPath != "/a/b/{y}" {
+			t.Fatalf("getNode() = %v, want /a/b/{y}", got)
+		}
+	}
+}
+
+func TestPathTrie_GetMatches(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/api/{param1}/items", value: 1},
+		pathAndValue{path: "/api/items", value: 2},
+		pathAndValue{path: "/api/{param1}/{param2}", value: 3},
+		pathAndValue{path: "/api/items/items", value: 4},
+	); err != nil {
+		t.Error(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "sorted by path params count",
+			args: args{
+				path: "/api/items/items",
+			},
+			want: []string{"/api/items/items", "/api/{param1}/items", "/api/{param1}/{param2}"},
+		},
+		{
+			name: "exact match first",
+			args: args{
+				path: "/api/{param1}/items",
+			},
+			want: []string{"/api/{param1}/items", "/api/{param1}/{param2}"},
+		},
+		{
+			name: "single match",
+			args: args{
+				path: "/api/items",
+			},
+			want: []string{"/api/items"},
+		},
+		{
+			name: "no match",
+			args: args{
+				path: "/api",
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, node := range pt.GetMatches(tt.args.path) {
+				got = append(got, node.FullPath)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_customIsPathParam(t *testing.T) {
+	pt := New()
+	pt.IsPathParam = func(segment string) bool {
+		return strings.HasPrefix(segment, ":") ||
+			(strings.HasPrefix(segment, "<") && strings.HasSuffix(segment, ">")) ||
+			util.IsPathParam(segment)
+	}
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/users/:id", value: 1},
+		pathAndValue{path: "/orders/<id>/items/{item}", value: 2},
+		pathAndValue{path: "/orders/<id>/items/last", value: 3},
+	); err != nil {
+		t.Error(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name                 string
+		args                 args
+		wantPath             string
+		wantPathParamCounter int
+	}{
+		{
+			name: "colon path param",
+			args: args{
+				path: "/users/42",
+			},
+			wantPath:             "/users/:id",
+			wantPathParamCounter: 1,
+		},
+		{
+			name: "mixed path params",
+			args: args{
+				path: "/orders/42/items/7",
+			},
+			wantPath:             "/orders/<id>/items/{item}",
+			wantPathParamCounter: 2,
+		},
+		{
+			name: "most accurate match",
+			args: args{
+				path: "/orders/42/items/last",
+			},
+			wantPath:             "/orders/<id>/items/last",
+			wantPathParamCounter: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := pt.getNode(tt.args.path)
+			if node == nil {
+				t.Fatalf("getNode() = nil, want %v", tt.wantPath)
+			}
+			if node.FullPath != tt.wantPath {
+				t.Errorf("getNode() FullPath = %v, want %v", node.FullPath, tt.wantPath)
+			}
+			if node.PathParamCounter != tt.wantPathParamCounter {
+				t.Errorf("getNode() PathParamCounter = %v, want %v", node.PathParamCounter, tt.wantPathParamCounter)
+			}
+		})
+	}
+}
+
+func TestPathTrie_wildcard(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/assets/*/icon", value: 1},
+		pathAndValue{path: "/assets/logo/*", value: 2},
+		pathAndValue{path: "/assets/*/*", value: 3},
+		pathAndValue{path: "/assets/{name}/icon/{size}", value: 4},
+	); err != nil {
+		t.Error(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantPath string
+	}{
+		{
+			name: "wildcard match",
+			args: args{
+				path: "/assets/favicon/icon",
+			},
+			wantPath: "/assets/*/icon",
+		},
+		{
+			name: "wildcard tie-break on static prefix",
+			args: args{
+				path: "/assets/logo/icon",
+			},
+			wantPath: "/assets/logo/*",
+		},
+		{
+			name: "less wildcards match",
+			args: args{
+				path: "/assets/logo/banner",
+			},
+			wantPath: "/assets/logo/*",
+		},
+		{
+			name: "wildcard and path param mix",
+			args: args{
+				path: "/assets/logo/icon/16",
+			},
+			wantPath: "/assets/{name}/icon/{size}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath, _, gotFound := pt.GetPathAndValue(tt.args.path)
+			if !gotFound || gotPath != tt.wantPath {
+				t.Errorf("GetPathAndValue() = %v, %v, want %v", gotPath, gotFound, tt.wantPath)
+			}
+		})
+	}
+
+	if got := pt.getNode("/assets/*/*").PathParamCounter; got != 2 {
+		t.Errorf("PathParamCounter = %v, want 2", got)
+	}
+}
+
+func TestPathTrie_catchAll(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/static/**", value: 1},
+		pathAndValue{path: "/static/css/{file}", value: 2},
+		pathAndValue{path: "/static/{a}/{b}/{c}/{d}", value: 3},
+	); err != nil {
+		t.Error(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantPath  string
+		wantFound bool
+	}{
+		{
+			name: "catch-all match on multiple segments",
+			args: args{
+				path: "/static/css/app/v2.css",
+			},
+			wantPath:  "/static/**",
+			wantFound: true,
+		},
+		{
+			name: "catch-all match on single segment",
+			args: args{
+				path: "/static/favicon.ico",
+			},
+			wantPath:  "/static/**",
+			wantFound: true,
+		},
+		{
+			name: "more specific path wins",
+			args: args{
+				path: "/static/css/app.css",
+			},
+			wantPath:  "/static/css/{file}",
+			wantFound: true,
+		},
+		{
+			name: "path params win over catch-all",
+			args: args{
+				path: "/static/js/app/v2/index.js",
+			},
+			wantPath:  "/static/{a}/{b}/{c}/{d}",
+			wantFound: true,
+		},
+		{
+			name: "catch-all needs at least one segment",
+			args: args{
+				path: "/static",
+			},
+			wantPath:  "",
+			wantFound: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath, _, gotFound := pt.GetPathAndValue(tt.args.path)
+			if gotPath != tt.wantPath || gotFound != tt.wantFound {
+				t.Errorf("GetPathAndValue() = %v, %v, want %v, %v", gotPath, gotFound, tt.wantPath, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestPathTrie_caseInsensitive(t *testing.T) {
+	pt := New()
+	pt.CaseInsensitive = true
+	if !pt.Insert("/v1/Users/{ID}", 1) {
+		t.Errorf("Insert() = false, want true")
+	}
+	if pt.Insert("/V1/users/{ID}", 2) {
+		t.Errorf("Insert() = true, want false")
+	}
+	if !pt.Insert("/v1/users/{id}", 3) {
+		t.Errorf("Insert() of differently-cased path param = false, want true")
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantPath string
+		want     any
+	}{
+		{
+			name: "same casing",
+			args: args{
+				path: "/v1/Users/{ID}",
+			},
+			wantPath: "/v1/Users/{ID}",
+			want:     2,
+		},
+		{
+			name: "different casing",
+			args: args{
+				path: "/V1/USERS/42",
+			},
+			wantPath: "/v1/Users/{ID}",
+			want:     2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath, got, _ := pt.GetPathAndValue(tt.args.path)
+			if gotPath != tt.wantPath || got != tt.want {
+				t.Errorf("GetPathAndValue() = %v, %v, want %v, %v", gotPath, got, tt.wantPath, tt.want)
+			}
+		})
+	}
+
+	got := pt.GetChildren()
+	sort.Strings(got)
+	if want := []string{"/v1", "/v1/Users", "/v1/Users/{ID}", "/v1/Users/{id}"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetChildren() = %v, want %v", got, want)
+	}
+
+	if !pt.Delete("/V1/USERS/{ID}") {
+		t.Errorf("Delete() = false, want true")
+	}
+}
+
 func TestPathTrie_GetValue(t *testing.T) {
 	pt := New()
 	if err := populateDummyPathsAndValue(pt,
@@ -180,6 +526,63 @@ func TestPathTrie_GetValue(t *testing.T) {
 	}
 }
 
+func TestPathTrie_GetValueOK(t *testing.T) {
+	var nilStats *struct{}
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/api/{param1}/items", value: 1},
+		pathAndValue{path: "/api/{param1}/cats", value: nilStats},
+	); err != nil {
+		t.Error(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantValue interface{}
+		wantFound bool
+	}{
+		{
+			name: "match",
+			args: args{
+				path: "/api/1/items",
+			},
+			wantValue: 1,
+			wantFound: true,
+		},
+		{
+			name: "match nil pointer value",
+			args: args{
+				path: "/api/1/cats",
+			},
+			wantValue: nilStats,
+			wantFound: true,
+		},
+		{
+			name: "no match",
+			args: args{
+				path: "api/items/cat",
+			},
+			wantValue: nil,
+			wantFound: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotValue, gotFound := pt.GetValueOK(tt.args.path)
+			if !reflect.DeepEqual(gotValue, tt.wantValue) {
+				t.Errorf("GetValueOK() gotValue = %v, wantValue %v", gotValue, tt.wantValue)
+			}
+			if gotFound != tt.wantFound {
+				t.Errorf("GetValueOK() gotFound = %v, wantFound %v", gotFound, tt.wantFound)
+			}
+		})
+	}
+}
+
 func TestPathTrie_GetPathAndValue(t *testing.T) {
 	pt := New()
 	if err := populateDummyPathsAndValue(pt,
@@ -232,7 +635,7 @@ func TestPathTrie_GetPathAndValue(t *testing.T) {
 	}
 }
 
-func TestPathTrieMap_getMatchNodes(t *testing.T) {
+func TestPathTrieMap_getMatchNodesFunc(t *testing.T) {
 	type args struct {
 		segments []string
 		idx      int
@@ -255,6 +658,7 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 									Name:             "test",
 									FullPath:         "/api/{param1}/test",
 									PathParamCounter: 1,
+									SegmentCount:     3,
 									Value:            1,
 								},
 								"{param2}": {
@@ -262,16 +666,19 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 									Name:             "{param2}",
 									FullPath:         "/api/{param1}/{param2}",
 									PathParamCounter: 2,
+									SegmentCount:     3,
 									Value:            2,
 								},
 							},
 							Name:             "{param1}",
 							FullPath:         "/api/{param1}",
 							PathParamCounter: 1,
+							SegmentCount:     2,
 						},
 					},
-					Name:     "api",
-					FullPath: "/api",
+					Name:         "api",
+					FullPath:     "/api",
+					SegmentCount: 1,
 				},
 			},
 			args: args{
@@ -284,6 +691,7 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 					Name:             "test",
 					FullPath:         "/api/{param1}/test",
 					PathParamCounter: 1,
+					SegmentCount:     3,
 					Value:            1,
 				},
 				{
@@ -291,6 +699,7 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 					Name:             "{param2}",
 					FullPath:         "/api/{param1}/{param2}",
 					PathParamCounter: 2,
+					SegmentCount:     3,
 					Value:            2,
 				},
 			},
@@ -309,12 +718,14 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 											Name:             "cats",
 											FullPath:         "/api/{param1}/test/cats",
 											PathParamCounter: 1,
+											SegmentCount:     4,
 											Value:            1,
 										},
 									},
 									Name:             "test",
 									FullPath:         "/api/{param1}/test",
 									PathParamCounter: 1,
+									SegmentCount:     3,
 									Value:            nil,
 								},
 								"{param2}": {
@@ -322,16 +733,19 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 									Name:             "{param2}",
 									FullPath:         "/api/{param1}/{param2}",
 									PathParamCounter: 2,
+									SegmentCount:     3,
 									Value:            2,
 								},
 							},
 							Name:             "{param1}",
 							FullPath:         "/api/{param1}",
 							PathParamCounter: 1,
+							SegmentCount:     2,
 						},
 					},
-					Name:     "api",
-					FullPath: "/api",
+					Name:         "api",
+					FullPath:     "/api",
+					SegmentCount: 1,
 				},
 			},
 			args: args{
@@ -344,6 +758,7 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 					Name:             "{param2}",
 					FullPath:         "/api/{param1}/{param2}",
 					PathParamCounter: 2,
+					SegmentCount:     3,
 					Value:            2,
 				},
 			},
@@ -360,6 +775,7 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 									Name:             "test",
 									FullPath:         "/api/{param1}/test",
 									PathParamCounter: 1,
+									SegmentCount:     3,
 									Value:            1,
 								},
 								"{param2}": {
@@ -367,17 +783,20 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 									Name:             "{param2}",
 									FullPath:         "/api/{param1}/{param2}",
 									PathParamCounter: 2,
+									SegmentCount:     3,
 									Value:            2,
 								},
 							},
 							Name:             "{param1}",
 							FullPath:         "/api/{param1}",
 							PathParamCounter: 1,
+							SegmentCount:     2,
 							Value:            nil,
 						},
 					},
-					Name:     "api",
-					FullPath: "/api",
+					Name:         "api",
+					FullPath:     "/api",
+					SegmentCount: 1,
 				},
 			},
 			args: args{
@@ -389,7 +808,8 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tt.trie.getMatchNodes(tt.args.segments, tt.args.idx)
+			pt := New()
+			got := pt.getMatchNodesFunc(tt.trie, tt.args.segments, tt.args.idx, hasValue, nil)
 			sort.Slice(got, func(i, j int) bool {
 				return got[i].FullPath < got[j].FullPath
 			})
@@ -397,7 +817,7 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 				return tt.want[i].FullPath < tt.want[j].FullPath
 			})
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("getMatchNodes() = %v, want %v", got, tt.want)
+				t.Errorf("getMatchNodesFunc() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -406,9 +826,8 @@ func TestPathTrieMap_getMatchNodes(t *testing.T) {
 func Test_getMostAccurateNode(t *testing.T) {
 	pt := New()
 	type args struct {
-		nodes       []*TrieNode
-		path        string
-		segmentsLen int
+		nodes []*TrieNode
+		path  string
 	}
 	tests := []struct {
 		name string
@@ -422,8 +841,7 @@ func Test_getMostAccurateNode(t *testing.T) {
 					pt.createPathTrieNode([]string{"", "api", "{param1}", "test"}, 3, true, 1),
 					pt.createPathTrieNode([]string{"", "api", "{param1}", "{param2}"}, 3, true, 2),
 				},
-				path:        "/api/{param1}/test",
-				segmentsLen: 4,
+				path: "/api/{param1}/test",
 			},
 			want: pt.createPathTrieNode([]string{"", "api", "{param1}", "test"}, 3, true, 1),
 		},
@@ -434,8 +852,7 @@ func Test_getMostAccurateNode(t *testing.T) {
 					pt.createPathTrieNode([]string{"", "api", "{param1}", "test", "{param2}"}, 4, true, 1),
 					pt.createPathTrieNode([]string{"", "api", "{param1}", "{param2}", "{param3}"}, 4, true, 2),
 				},
-				path:        "/api/cats/test/dogs",
-				segmentsLen: 5,
+				path: "/api/cats/test/dogs",
 			},
 			want: pt.createPathTrieNode([]string{"", "api", "{param1}", "test", "{param2}"}, 4, true, 1),
 		},
@@ -445,15 +862,36 @@ func Test_getMostAccurateNode(t *testing.T) {
 				nodes: []*TrieNode{
 					pt.createPathTrieNode([]string{"", "api", "{param1}", "test"}, 3, true, 1),
 				},
-				path:        "/api/cats/test",
-				segmentsLen: 4,
+				path: "/api/cats/test",
 			},
 			want: pt.createPathTrieNode([]string{"", "api", "{param1}", "test"}, 3, true, 1),
 		},
+		{
+			name: "same path params count - longest static prefix match",
+			args: args{
+				nodes: []*TrieNode{
+					pt.createPathTrieNode([]string{"", "a", "{x}", "c"}, 3, true, 1),
+					pt.createPathTrieNode([]string{"", "a", "b", "{y}"}, 3, true, 2),
+				},
+				path: "/a/b/c",
+			},
+			want: pt.createPathTrieNode([]string{"", "a", "b", "{y}"}, 3, true, 2),
+		},
+		{
+			name: "same path params count and static prefix - lexicographic match",
+			args: args{
+				nodes: []*TrieNode{
+					pt.createPathTrieNode([]string{"", "a", "{y}", "{w}", "d"}, 4, true, 1),
+					pt.createPathTrieNode([]string{"", "a", "{x}", "c", "{z}"}, 4, true, 2),
+				},
+				path: "/a/1/c/d",
+			},
+			want: pt.createPathTrieNode([]string{"", "a", "{x}", "c", "{z}"}, 4, true, 2),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := getMostAccurateNode(tt.args.nodes, tt.args.path, tt.args.segmentsLen); !reflect.DeepEqual(got, tt.want) {
+			if got := pt.getMostAccurateNode(tt.args.nodes, tt.args.path); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("getMostAccurateNode() = %v, want %v", got, tt.want)
 			}
 		})
@@ -509,7 +947,8 @@ func TestPathTrieNode_isNameMatch(t *testing.T) {
 			node := &TrieNode{
 				Name: tt.fields.Name,
 			}
-			if got := node.isNameMatch(tt.args.segment); got != tt.want {
+			pt := New()
+			if got := pt.isNameMatch(node, tt.args.segment); got != tt.want {
 				t.Errorf("isNameMatch() = %v, want %v", got, tt.want)
 			}
 		})
@@ -595,7 +1034,8 @@ func Test_countPathParam(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := countPathParam(tt.args.segments); got != tt.want {
+			pt := New()
+			if got := pt.countPathParam(tt.args.segments); got != tt.want {
 				t.Errorf("countPathParam() = %v, want %v", got, tt.want)
 			}
 		})
@@ -627,6 +1067,7 @@ func TestPathTrie_createPathTrieNode(t *testing.T) {
 				Name:             "{param}",
 				FullPath:         "/api/{param}",
 				PathParamCounter: 1,
+				SegmentCount:     2,
 				Value:            1,
 			},
 		},
@@ -643,6 +1084,7 @@ func TestPathTrie_createPathTrieNode(t *testing.T) {
 				Name:             "{param3}",
 				FullPath:         "/api/{param1}/{param2}/{param3}",
 				PathParamCounter: 3,
+				SegmentCount:     4,
 				Value:            nil,
 			},
 		},
@@ -659,6 +1101,7 @@ func TestPathTrie_createPathTrieNode(t *testing.T) {
 				Name:             "api",
 				FullPath:         "/api",
 				PathParamCounter: 0,
+				SegmentCount:     1,
 				Value:            nil,
 			},
 		},
@@ -716,6 +1159,7 @@ func TestPathTrie_InsertMerge(t *testing.T) {
 							Name:             "api",
 							FullPath:         "/api",
 							PathParamCounter: 0,
+							SegmentCount:     1,
 							Value:            1,
 						},
 					},
@@ -737,6 +1181,7 @@ func TestPathTrie_InsertMerge(t *testing.T) {
 								Name:             "api",
 								FullPath:         "/api",
 								PathParamCounter: 0,
+								SegmentCount:     1,
 								Value:            1,
 							},
 						},
@@ -762,6 +1207,7 @@ func TestPathTrie_InsertMerge(t *testing.T) {
 							Name:             "api",
 							FullPath:         "/api",
 							PathParamCounter: 0,
+							SegmentCount:     1,
 							Value:            2,
 						},
 					},
@@ -783,6 +1229,7 @@ func TestPathTrie_InsertMerge(t *testing.T) {
 								Name:             "api",
 								FullPath:         "/api",
 								PathParamCounter: 0,
+								SegmentCount:     1,
 								Value:            1,
 							},
 						},
@@ -810,12 +1257,14 @@ func TestPathTrie_InsertMerge(t *testing.T) {
 									Name:             "",
 									FullPath:         "/api/",
 									PathParamCounter: 0,
+									SegmentCount:     1,
 									Value:            2,
 								},
 							},
 							Name:             "api",
 							FullPath:         "/api",
 							PathParamCounter: 0,
+							SegmentCount:     1,
 							Value:            1,
 						},
 					},
@@ -837,6 +1286,7 @@ func TestPathTrie_InsertMerge(t *testing.T) {
 								Name:             "api",
 								FullPath:         "/api",
 								PathParamCounter: 0,
+								SegmentCount:     1,
 								Value:            1,
 							},
 						},
@@ -864,12 +1314,14 @@ func TestPathTrie_InsertMerge(t *testing.T) {
 									Name:             "{param}",
 									FullPath:         "/api/{param}",
 									PathParamCounter: 1,
+									SegmentCount:     2,
 									Value:            2,
 								},
 							},
 							Name:             "api",
 							FullPath:         "/api",
 							PathParamCounter: 0,
+							SegmentCount:     1,
 							Value:            1,
 						},
 					},
@@ -898,22 +1350,26 @@ func TestPathTrie_InsertMerge(t *testing.T) {
 														Name:             "{itemID}",
 														FullPath:         "/carts/{customerID}/items/{itemID}",
 														PathParamCounter: 1,
+														SegmentCount:     4,
 														Value:            "1",
 													},
 												},
 												Name:             "items",
 												FullPath:         "/carts/{customerID}/items",
 												PathParamCounter: 1,
+												SegmentCount:     3,
 											},
 										},
 										Name:             "{customerID}",
 										FullPath:         "/carts/{customerID}",
 										PathParamCounter: 0,
+										SegmentCount:     2,
 									},
 								},
 								Name:             "carts",
 								FullPath:         "/carts",
 								PathParamCounter: 0,
+								SegmentCount:     1,
 							},
 						},
 						Name:             "",
@@ -944,23 +1400,27 @@ func TestPathTrie_InsertMerge(t *testing.T) {
 													Name:             "{itemID}",
 													FullPath:         "/carts/{customerID}/items/{itemID}",
 													PathParamCounter: 1,
+													SegmentCount:     4,
 													Value:            "1",
 												},
 											},
 											Name:             "items",
 											FullPath:         "/carts/{customerID}/items",
 											PathParamCounter: 1,
+											SegmentCount:     3,
 											Value:            "2",
 										},
 									},
 									Name:             "{customerID}",
 									FullPath:         "/carts/{customerID}",
 									PathParamCounter: 0,
+									SegmentCount:     2,
 								},
 							},
 							Name:             "carts",
 							FullPath:         "/carts",
 							PathParamCounter: 0,
+							SegmentCount:     1,
 						},
 					},
 					Name:             "",
@@ -987,6 +1447,40 @@ func TestPathTrie_InsertMerge(t *testing.T) {
 	}
 }
 
+func TestPathTrie_OnOverwrite(t *testing.T) {
+	type overwrite struct {
+		path string
+		old  any
+		newV any
+	}
+	var got []overwrite
+
+	pt := New()
+	pt.OnOverwrite = func(path string, old, newV any) {
+		got = append(got, overwrite{path: path, old: old, newV: newV})
+	}
+	sumMerge := func(existing, newV *any) {
+		if *existing == nil {
+			*existing = *newV
+			return
+		}
+		*existing = (*existing).(int) + (*newV).(int)
+	}
+
+	pt.InsertMerge("/v1/users/{id}", 1, sumMerge)
+	pt.InsertMerge("/v1/users", 2, sumMerge)
+	pt.InsertMerge("/v1/users/{id}", 3, sumMerge)
+	pt.InsertMerge("/v1/users/{id}", 5, sumMerge)
+
+	want := []overwrite{
+		{path: "/v1/users/{id}", old: 1, newV: 3},
+		{path: "/v1/users/{id}", old: 4, newV: 5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OnOverwrite() calls = %v, want %v", got, want)
+	}
+}
+
 func TestPathTrie_GetChildren(t *testing.T) {
 	type fields struct {
 		PathsAndValue []pathAndValue
@@ -1052,13 +1546,13 @@ func TestPathTrie_GetChildren(t *testing.T) {
 			},
 		},
 		{
-			name: "root path only returns no path",
+			name: "root path only returns root path",
 			fields: fields{
 				PathsAndValue: []pathAndValue{
 					{path: "/", value: 1},
 				},
 			},
-			want: []string{},
+			want: []string{"/"},
 		},
 		{
 			name: "multiple paths with trailing slashes returns correct children ",
@@ -1071,8 +1565,11 @@ func TestPathTrie_GetChildren(t *testing.T) {
 			},
 			want: []string{
 				"/api",
+				"/api/",
 				"/api/items",
+				"/api/items/",
 				"/api/items/cat",
+				"/api/items/cat/",
 			},
 		},
 	}
@@ -1097,6 +1594,79 @@ func TestPathTrie_GetChildren(t *testing.T) {
 	}
 }
 
+func TestPathTrie_GetChildrenToDepth(t *testing.T) {
+	paths := []pathAndValue{
+		{path: "/api/items", value: 1},
+		{path: "/api/items/cat", value: 2},
+		{path: "/api/{param1}/{param2}/{param3}", value: 3},
+		{path: "relative/path", value: 4},
+	}
+	type args struct {
+		maxDepth int
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "unlimited depth",
+			args: args{
+				maxDepth: 0,
+			},
+			want: []string{
+				"/api",
+				"/api/items",
+				"/api/items/cat",
+				"/api/{param1}",
+				"/api/{param1}/{param2}",
+				"/api/{param1}/{param2}/{param3}",
+				"relative",
+				"relative/path",
+			},
+		},
+		{
+			name: "depth 1",
+			args: args{
+				maxDepth: 1,
+			},
+			want: []string{
+				"/api",
+				"relative",
+			},
+		},
+		{
+			name: "path params count toward depth",
+			args: args{
+				maxDepth: 3,
+			},
+			want: []string{
+				"/api",
+				"/api/items",
+				"/api/items/cat",
+				"/api/{param1}",
+				"/api/{param1}/{param2}",
+				"relative",
+				"relative/path",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			if err := populateDummyPathsAndValue(pt, paths...); err != nil {
+				t.Fatal(err)
+			}
+
+			got := pt.GetChildrenToDepth(tt.args.maxDepth)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetChildrenToDepth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func marshal(trie PathToTrieNode) any {
 	trieBytes, _ := json.Marshal(trie)
 	return string(trieBytes)
@@ -1110,3 +1680,641 @@ func populateDummyPathsAndValue(trie PathTrie, pathAndValues ...pathAndValue) er
 	}
 	return nil
 }
+
+func TestPathTrie_MatchDetail(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/users/me", value: 2},
+		pathAndValue{path: "/v1/static/**", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantFullPath string
+		wantExact    bool
+		wantOk       bool
+	}{
+		{
+			name: "literal",
+			args: args{
+				path: "/v1/users/me",
+			},
+			wantFullPath: "/v1/users/me",
+			wantExact:    true,
+			wantOk:       true,
+		},
+		{
+			name: "template",
+			args: args{
+				path: "/v1/users/42",
+			},
+			wantFullPath: "/v1/users/{id}",
+			wantExact:    false,
+			wantOk:       true,
+		},
+		{
+			name: "template itself",
+			args: args{
+				path: "/v1/users/{id}",
+			},
+			wantFullPath: "/v1/users/{id}",
+			wantExact:    true,
+			wantOk:       true,
+		},
+		{
+			name: "catch-all",
+			args: args{
+				path: "/v1/static/app.css",
+			},
+			wantFullPath: "/v1/static/**",
+			wantExact:    false,
+			wantOk:       true,
+		},
+		{
+			name: "no match",
+			args: args{
+				path: "/v2/users",
+			},
+			wantFullPath: "",
+			wantExact:    false,
+			wantOk:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotNode, gotExact, gotOk := pt.MatchDetail(tt.args.path)
+			gotFullPath := ""
+			if gotNode != nil {
+				gotFullPath = gotNode.FullPath
+			}
+			if gotFullPath != tt.wantFullPath {
+				t.Errorf("MatchDetail() gotNode.FullPath = %v, want %v", gotFullPath, tt.wantFullPath)
+			}
+			if gotExact != tt.wantExact {
+				t.Errorf("MatchDetail() gotExact = %v, want %v", gotExact, tt.wantExact)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("MatchDetail() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestNewWithPathSeparatorChecked(t *testing.T) {
+	type args struct {
+		pathSeparator string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "non-empty separator",
+			args: args{
+				pathSeparator: "::",
+			},
+		},
+		{
+			name: "empty separator",
+			args: args{
+				pathSeparator: "",
+			},
+			wantErr: ErrEmptySeparator,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewWithPathSeparatorChecked(tt.args.pathSeparator)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewWithPathSeparatorChecked() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.PathSeparator != tt.args.pathSeparator || got.Trie == nil) {
+				t.Errorf("NewWithPathSeparatorChecked() = %v, want a trie separated by %v", got, tt.args.pathSeparator)
+			}
+		})
+	}
+}
+
+func TestNewWithPathSeparator_emptySeparator(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("NewWithPathSeparator() didn't panic")
+		}
+	}()
+	NewWithPathSeparator("")
+}
+
+func TestPathTrie_ParamPositions(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}/posts/{postId}", value: 1},
+		pathAndValue{path: "/v1/users", value: 2},
+		pathAndValue{path: "{tenant}/files/**", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []int
+	}{
+		{
+			name: "templated path",
+			args: args{
+				path: "/v1/users/42/posts/7",
+			},
+			want: []int{3, 5},
+		},
+		{
+			name: "static path",
+			args: args{
+				path: "/v1/users",
+			},
+			want: []int{},
+		},
+		{
+			name: "relative path with catch-all",
+			args: args{
+				path: "acme/files/a/b",
+			},
+			want: []int{0, 2},
+		},
+		{
+			name: "no match",
+			args: args{
+				path: "/v2/users",
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pt.ParamPositions(tt.args.path)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParamPositions() = %v, want %v", got, tt.want)
+			}
+			if node := pt.getNode(tt.args.path); node != nil && len(got) != node.PathParamCounter {
+				t.Errorf("len(ParamPositions()) = %v, want PathParamCounter %v", len(got), node.PathParamCounter)
+			}
+		})
+	}
+
+	pt.Compress()
+	if got := pt.ParamPositions("/v1/users/42/posts/7"); !reflect.DeepEqual(got, []int{3, 5}) {
+		t.Errorf("ParamPositions() after Compress() = %v, want [3 5]", got)
+	}
+}
+
+func TestPathTrie_GetValueSegments(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/users/me", value: 2},
+		pathAndValue{path: "/v1/{resource}/{id}", value: 3},
+		pathAndValue{path: "/v1/files/**", value: 4},
+		pathAndValue{path: "/v1/users/", value: 5},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lookups by segments behave like lookups by path.
+	for _, path := range []string{
+		"/v1/users/42", "/v1/users/me", "/v1/orders/7", "/v1/files/a/b", "/v1/users/", "/v1/users/{id}", "/v2", "",
+	} {
+		segments := strings.Split(path, "/")
+		gotVal, gotOK := pt.GetValueSegments(segments)
+		wantVal, wantOK := pt.GetValueOK(path)
+		if gotVal != wantVal || gotOK != wantOK {
+			t.Errorf("GetValueSegments(%q) = %v, %v, want %v, %v", segments, gotVal, gotOK, wantVal, wantOK)
+		}
+		if !reflect.DeepEqual(segments, strings.Split(path, "/")) {
+			t.Errorf("GetValueSegments() modified segments to %q", segments)
+		}
+	}
+}
+
+func TestPathTrie_isFullPathSegments(t *testing.T) {
+	type args struct {
+		fullPath string
+		segments []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "same path",
+			args: args{
+				fullPath: "/v1/users",
+				segments: []string{"", "v1", "users"},
+			},
+			want: true,
+		},
+		{
+			name: "longer full path",
+			args: args{
+				fullPath: "/v1/users/",
+				segments: []string{"", "v1", "users"},
+			},
+			want: false,
+		},
+		{
+			name: "shorter full path",
+			args: args{
+				fullPath: "/v1",
+				segments: []string{"", "v1", "users"},
+			},
+			want: false,
+		},
+		{
+			name: "different segment",
+			args: args{
+				fullPath: "/v1/users",
+				segments: []string{"", "v1", "usera"},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := New()
+			if got := pt.isFullPathSegments(tt.args.fullPath, tt.args.segments); got != tt.want {
+				t.Errorf("isFullPathSegments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newBenchmarkLookups returns a trie of templated paths and concrete paths
+// matching them.
+func newBenchmarkLookups(n int) (PathTrie, []string) {
+	pt := New()
+	paths := make([]string, n)
+	for idx := range paths {
+		pt.Insert(fmt.Sprintf("/api/v%d/users/{id}/orders/{orderId}", idx%100), idx)
+		paths[idx] = fmt.Sprintf("/api/v%d/users/%d/orders/%d", idx%100, idx, idx)
+	}
+	return pt, paths
+}
+
+func BenchmarkPathTrie_GetValue(b *testing.B) {
+	pt, paths := newBenchmarkLookups(10_000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, path := range paths {
+			pt.GetValue(path)
+		}
+	}
+}
+
+func BenchmarkPathTrie_GetValueSegments(b *testing.B) {
+	pt, paths := newBenchmarkLookups(10_000)
+	segments := make([][]string, len(paths))
+	for idx, path := range paths {
+		segments[idx] = strings.Split(path, "/")
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range segments {
+			pt.GetValueSegments(s)
+		}
+	}
+}
+
+func FuzzInsertMerge(f *testing.F) {
+	for _, seed := range []struct {
+		path      string
+		separator string
+		options   uint8
+	}{
+		{path: "/v1/users/{id}", separator: "/", options: 0},
+		{path: "/", separator: "/", options: 0xff},
+		{path: "////", separator: "/", options: 0x02},
+		{path: "", separator: "/", options: 0xff},
+		{path: "/v1/\x00/users", separator: "/", options: 0},
+		{path: "/" + strings.Repeat("a", 1<<16), separator: "/", options: 0},
+		{path: `\/\/\`, separator: "/", options: 0x40},
+		{path: "a::::b::", separator: "::", options: 0x0f},
+		{path: "/**/*/{a}/{b}/**", separator: "/", options: 0x20},
+		{path: "/%2f%25%zz%", separator: "/", options: 0x08},
+		{path: "?#/a?b#c", separator: "/", options: 0x04},
+	} {
+		f.Add(seed.path, seed.separator, seed.options)
+	}
+
+	f.Fuzz(func(t *testing.T, path, separator string, options uint8) {
+		pt, err := NewWithPathSeparatorChecked(separator)
+		if err != nil {
+			return
+		}
+		pt.TrimTrailingSeparator = options&0x01 != 0
+		pt.CollapseEmptySegments = options&0x02 != 0
+		pt.StripQueryAndFragment = options&0x04 != 0
+		pt.DecodeSegments = options&0x08 != 0
+		pt.CaseInsensitive = options&0x10 != 0
+		pt.UnifyPathParams = options&0x20 != 0
+		if options&0x40 != 0 {
+			pt.EscapeByte = '\\'
+		}
+		pt.RejectEmptySegments = options&0x80 != 0
+		pt.Insert("/v1/users/{id}", 1)
+
+		if _, err := pt.TryInsertMerge(path, 2, func(existing, newV *any) {
+			*existing = *newV
+		}); err != nil {
+			return
+		}
+		if _, ok := pt.GetValueOK(path); !ok {
+			t.Errorf("GetValueOK(%q) found no value after inserting it", path)
+		}
+
+		pt.Compress()
+		if _, ok := pt.GetValueOK(path); !ok {
+			t.Errorf("GetValueOK(%q) found no value after Compress()", path)
+		}
+		pt.Delete(path)
+		pt.Prune()
+		_ = pt.NodeCount()
+	})
+}
+
+// newBenchmarkParamTrie returns a deep trie mixing static and path param
+// segments at every level, and a path matching several of its nodes.
+func newBenchmarkParamTrie(depth int) (PathTrie, string) {
+	pt := New()
+	var paths []string
+	paths = append(paths, "")
+	for level := 0; level < depth; level++ {
+		var next []string
+		for _, path := range paths {
+			next = append(next, path+"/{p"+strconv.Itoa(level)+"}", path+"/s"+strconv.Itoa(level))
+		}
+		paths = next
+	}
+	for idx, path := range paths {
+		pt.Insert(path, idx)
+	}
+
+	var path strings.Builder
+	for level := 0; level < depth; level++ {
+		path.WriteString("/s" + strconv.Itoa(level))
+	}
+	return pt, path.String()
+}
+
+func BenchmarkPathTrie_getMatches(b *testing.B) {
+	pt, path := newBenchmarkParamTrie(8)
+	segments := pt.splitPath(path)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		pt.getMatches(segments)
+	}
+}
+
+func TestTrieNode_MatchSpecificity(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/me", value: 1},
+		pathAndValue{path: "/{version}/{resource}/{id}", value: 2},
+		pathAndValue{path: "/v1/users/{id}/posts/", value: 3},
+		pathAndValue{path: "/files/**", value: 4},
+		pathAndValue{path: "/", value: 5},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "all static",
+			args: args{
+				path: "/v1/users/me",
+			},
+			want: 1,
+		},
+		{
+			name: "all params",
+			args: args{
+				path: "/v2/orders/42",
+			},
+			want: 0,
+		},
+		{
+			name: "mixed with trailing separator",
+			args: args{
+				path: "/v1/users/42/posts/",
+			},
+			want: 0.75,
+		},
+		{
+			name: "catch-all",
+			args: args{
+				path: "/files/a/b/c",
+			},
+			want: 0.5,
+		},
+		{
+			name: "root",
+			args: args{
+				path: "/",
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := pt.getNode(tt.args.path)
+			if got := node.MatchSpecificity(); got != tt.want {
+				t.Errorf("MatchSpecificity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrieNode_MatchSpecificity_IsPathParam(t *testing.T) {
+	pt := NewWithPathSeparator(".")
+	pt.IsPathParam = func(segment string) bool {
+		return strings.HasPrefix(segment, ":")
+	}
+	pt.Insert(".v1.users.:id", 1)
+
+	if got := pt.getNode(".v1.users.42").MatchSpecificity(); got != 2.0/3 {
+		t.Errorf("MatchSpecificity() = %v, want %v", got, 2.0/3)
+	}
+}
+
+func TestPathTrie_GetValueOrDefault(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/users/{id}/posts", value: 2},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		path string
+		def  any
+	}
+	tests := []struct {
+		name string
+		args args
+		want any
+	}{
+		{
+			name: "matching path",
+			args: args{
+				path: "/v1/users/42",
+				def:  -1,
+			},
+			want: 1,
+		},
+		{
+			name: "missing path",
+			args: args{
+				path: "/v1/orders",
+				def:  -1,
+			},
+			want: -1,
+		},
+		{
+			name: "intermediate node without value",
+			args: args{
+				path: "/v1",
+				def:  "default",
+			},
+			want: "default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pt.GetValueOrDefault(tt.args.path, tt.args.def); got != tt.want {
+				t.Errorf("GetValueOrDefault() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_InsertSegments(t *testing.T) {
+	pt := New()
+	pt.Insert("/v1/users/{id}", 1)
+
+	tests := []struct {
+		name         string
+		segments     []string
+		wantNew      bool
+		wantFullPath string
+	}{
+		{
+			name:         "new path",
+			segments:     []string{"", "v1", "users", "{id}", "posts"},
+			wantNew:      true,
+			wantFullPath: "/v1/users/{id}/posts",
+		},
+		{
+			name:         "existing path",
+			segments:     []string{"", "v1", "users", "{id}"},
+			wantNew:      false,
+			wantFullPath: "/v1/users/{id}",
+		},
+		{
+			name:         "segment holding the separator",
+			segments:     []string{"", "v1", "files", "a/b"},
+			wantNew:      true,
+			wantFullPath: "/v1/files/a%2Fb",
+		},
+		{
+			name:         "segments are not normalized",
+			segments:     []string{"", "v1", "my%20file"},
+			wantNew:      true,
+			wantFullPath: "/v1/my%20file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments := slices.Clone(tt.segments)
+			if got := pt.InsertSegments(segments, tt.name); got != tt.wantNew {
+				t.Errorf("InsertSegments() = %v, want %v", got, tt.wantNew)
+			}
+			if !reflect.DeepEqual(segments, tt.segments) {
+				t.Errorf("InsertSegments() modified segments to %q", segments)
+			}
+
+			gotVal, ok := pt.GetValueSegments(tt.segments)
+			if !ok || gotVal != tt.name {
+				t.Errorf("GetValueSegments() = %v, %v, want %v, true", gotVal, ok, tt.name)
+			}
+			if gotPath, _, _ := pt.GetPathAndValue(tt.wantFullPath); gotPath != tt.wantFullPath {
+				t.Errorf("GetPathAndValue() = %v, want %v", gotPath, tt.wantFullPath)
+			}
+		})
+	}
+
+	// The encoded segment isn't taken for a compressed one.
+	if got := pt.GetValue("/v1/files/a/b"); got != nil {
+		t.Errorf("GetValue() = %v, want nil", got)
+	}
+	if names := pt.Trie[""].Child("v1").Child("files").ChildrenNames(); !reflect.DeepEqual(names, []string{"a%2Fb"}) {
+		t.Errorf("ChildrenNames() = %q, want [a%%2Fb]", names)
+	}
+}
+
+func TestPathTrie_InsertSegments_rejected(t *testing.T) {
+	pt := New()
+	pt.MaxDepth = 2
+	if pt.InsertSegments([]string{"", "v1", "users", "{id}"}, 1) {
+		t.Error("InsertSegments() = true beyond MaxDepth, want false")
+	}
+	if pt.InsertSegments(nil, 1) {
+		t.Error("InsertSegments() = true without segments, want false")
+	}
+
+	digits := NewWithPathSeparator("2")
+	if digits.InsertSegments([]string{"a", "b2c"}, 1) {
+		t.Error("InsertSegments() = true with an unencodable separator, want false")
+	}
+	if got := digits.NodeCount(); got != 0 {
+		t.Errorf("NodeCount() = %v, want 0", got)
+	}
+}
+
+func TestPathTrie_IsParamSegment(t *testing.T) {
+	pt := New()
+	for segment, want := range map[string]bool{"{id}": true, "*": true, "**": true, ":id": false, "users": false} {
+		if got := pt.IsParamSegment(segment); got != want {
+			t.Errorf("IsParamSegment(%s) = %v, want %v", segment, got, want)
+		}
+	}
+
+	pt.IsPathParam = func(segment string) bool {
+		return strings.HasPrefix(segment, ":")
+	}
+	for segment, want := range map[string]bool{"{id}": false, "*": true, ":id": true} {
+		if got := pt.IsParamSegment(segment); got != want {
+			t.Errorf("IsParamSegment(%s) with IsPathParam = %v, want %v", segment, got, want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"strings"
+)
+
+// LongestPrefixMatch descends the PathTrie as far as path allows and returns the
+// full path and value of the deepest value-holding node it passed through.
+// E.g. with only /v1/users/{id} registered, /v1/users/42/avatar/thumbnail
+// resolves to /v1/users/{id}. If several param branches reach the same depth,
+// the most accurate node is returned.
+func (pt *PathTrie) LongestPrefixMatch(path string) (fullPath string, val any, ok bool) {
+	segments := pt.splitPath(path)
+
+	node, _ := pt.getLongestPrefixNode(segments)
+	if node == nil {
+		return "", nil, false
+	}
+
+	return node.FullPath, node.Value, true
+}
+
+// MatchPrefix is like LongestPrefixMatch but returns the deepest value-holding
+// node matching a prefix of path along with the segments left unmatched, e.g.
+// with only /v1/users/{id} registered, /v1/users/42/avatar/thumbnail returns
+// /v1/users/{id} and [avatar thumbnail]. The remaining segments are empty on an
+// exact match.
+func (pt *PathTrie) MatchPrefix(path string) (node *TrieNode, remaining []string, ok bool) {
+	segments := pt.splitPath(path)
+
+	node, depth := pt.getLongestPrefixNode(segments)
+	if node == nil {
+		return nil, nil, false
+	}
+
+	return node, segments[depth:], true
+}
+
+// getPrefixNode returns the most accurate node matching prefix, whether or not
+// it holds a value. If prefix ends within a compressed node, that node is
+// returned, see matchNodePrefix.
+func (pt *PathTrie) getPrefixNode(prefix string) *TrieNode {
+	return pt.getPrefixNodeSegments(pt.splitPath(prefix))
+}
+
+// getPrefixNodeSegments is like getPrefixNode but takes the segments of the
+// prefix.
+func (pt *PathTrie) getPrefixNodeSegments(segments []string) *TrieNode {
+	prefix := strings.Join(segments, pt.PathSeparator)
+
+	mc := pt.newMatchContext(nil)
+	nodes := pt.appendMatchNodes(nil, pt.Trie, segments, 0, func(*TrieNode) bool {
+		return true
+	}, pt.matchNodePrefix, mc)
+	pt.reportTruncation(mc)
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	return pt.getMostAccurateNode(nodes, prefix)
+}
+
+// getLongestPrefixNode returns the deepest value-holding node matching a prefix
+// of segments, along with the number of segments it matched.
+func (pt *PathTrie) getLongestPrefixNode(segments []string) (*TrieNode, int) {
+	nodes, depth := pt.getLongestPrefixNodes(pt.Trie, segments, 0)
+	if len(nodes) == 0 {
+		return nil, 0
+	}
+	if len(nodes) == 1 {
+		return nodes[0], depth
+	}
+
+	prefix := strings.Join(segments[:depth], pt.PathSeparator)
+	return pt.getMostAccurateNode(nodes, prefix), depth
+}
+
+// getLongestPrefixNodes returns the value-holding nodes that match the longest
+// prefix of segments, along with the length of that prefix.
+func (pt *PathTrie) getLongestPrefixNodes(trie PathToTrieNode, segments []string, idx int) ([]*TrieNode, int) {
+	var nodes []*TrieNode
+	depth := 0
+
+	for _, node := range trie {
+		next, ok := pt.matchNode(node, segments, idx)
+		if !ok {
+			continue
+		}
+
+		// The node itself is a candidate, unless a deeper one is found below.
+		var candidates []*TrieNode
+		candidatesDepth := 0
+		if node.Value != nil {
+			candidatesDepth = next
+			if node.isCatchAll() {
+				candidatesDepth = len(segments)
+			}
+			candidates = []*TrieNode{node}
+		}
+		if next < len(segments) && !node.isCatchAll() {
+			if childNodes, childDepth := pt.getLongestPrefixNodes(node.Children, segments, next); len(childNodes) > 0 {
+				candidates, candidatesDepth = childNodes, childDepth
+			}
+		}
+
+		switch {
+		case candidatesDepth > depth:
+			nodes, depth = candidates, candidatesDepth
+		case candidatesDepth == depth && depth > 0:
+			nodes = append(nodes, candidates...)
+		}
+	}
+
+	return nodes, depth
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathTrie_LongestPrefixMatch(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1", value: 1},
+		pathAndValue{path: "/v1/users/{id}", value: 2},
+		pathAndValue{path: "/v1/users/{id}/avatar/{size}", value: 3},
+		pathAndValue{path: "/v1/{resource}/list", value: 4},
+		pathAndValue{path: "/v1/orders/list", value: 5},
+		pathAndValue{path: "/v1/static/**", value: 6},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantFullPath string
+		wantVal      any
+		wantOk       bool
+	}{
+		{
+			name: "exact match",
+			args: args{
+				path: "/v1/users/{id}",
+			},
+			wantFullPath: "/v1/users/{id}",
+			wantVal:      2,
+			wantOk:       true,
+		},
+		{
+			name: "deeper than registered path",
+			args: args{
+				path: "/v1/users/42/avatar",
+			},
+			wantFullPath: "/v1/users/{id}",
+			wantVal:      2,
+			wantOk:       true,
+		},
+		{
+			name: "deepest ancestor wins",
+			args: args{
+				path: "/v1/users/42/avatar/thumbnail/raw",
+			},
+			wantFullPath: "/v1/users/{id}/avatar/{size}",
+			wantVal:      3,
+			wantOk:       true,
+		},
+		{
+			name: "static branch is more accurate than param branch at same depth",
+			args: args{
+				path: "/v1/orders/list/extra",
+			},
+			wantFullPath: "/v1/orders/list",
+			wantVal:      5,
+			wantOk:       true,
+		},
+		{
+			name: "only root-level ancestor",
+			args: args{
+				path: "/v1",
+			},
+			wantFullPath: "/v1",
+			wantVal:      1,
+			wantOk:       true,
+		},
+		{
+			name: "catch-all consumes remaining segments",
+			args: args{
+				path: "/v1/static/css/app.css",
+			},
+			wantFullPath: "/v1/static/**",
+			wantVal:      6,
+			wantOk:       true,
+		},
+		{
+			name: "no match",
+			args: args{
+				path: "/v2/users",
+			},
+			wantFullPath: "",
+			wantVal:      nil,
+			wantOk:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFullPath, gotVal, gotOk := pt.LongestPrefixMatch(tt.args.path)
+			if gotFullPath != tt.wantFullPath {
+				t.Errorf("LongestPrefixMatch() gotFullPath = %v, want %v", gotFullPath, tt.wantFullPath)
+			}
+			if !reflect.DeepEqual(gotVal, tt.wantVal) {
+				t.Errorf("LongestPrefixMatch() gotVal = %v, want %v", gotVal, tt.wantVal)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("LongestPrefixMatch() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestPathTrie_MatchPrefix(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/static/**", value: 2},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantFullPath  string
+		wantRemaining []string
+		wantOk        bool
+	}{
+		{
+			name: "exact match",
+			args: args{
+				path: "/v1/users/42",
+			},
+			wantFullPath:  "/v1/users/{id}",
+			wantRemaining: []string{},
+			wantOk:        true,
+		},
+		{
+			name: "2-segment overflow",
+			args: args{
+				path: "/v1/users/42/avatar/thumbnail",
+			},
+			wantFullPath:  "/v1/users/{id}",
+			wantRemaining: []string{"avatar", "thumbnail"},
+			wantOk:        true,
+		},
+		{
+			name: "catch-all leaves no remaining segments",
+			args: args{
+				path: "/v1/static/css/app.css",
+			},
+			wantFullPath:  "/v1/static/**",
+			wantRemaining: []string{},
+			wantOk:        true,
+		},
+		{
+			name: "no match",
+			args: args{
+				path: "/v1/orders",
+			},
+			wantFullPath:  "",
+			wantRemaining: nil,
+			wantOk:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotNode, gotRemaining, gotOk := pt.MatchPrefix(tt.args.path)
+			gotFullPath := ""
+			if gotNode != nil {
+				gotFullPath = gotNode.FullPath
+			}
+			if gotFullPath != tt.wantFullPath {
+				t.Errorf("MatchPrefix() gotNode.FullPath = %v, want %v", gotFullPath, tt.wantFullPath)
+			}
+			if !reflect.DeepEqual(gotRemaining, tt.wantRemaining) {
+				t.Errorf("MatchPrefix() gotRemaining = %v, want %v", gotRemaining, tt.wantRemaining)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("MatchPrefix() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
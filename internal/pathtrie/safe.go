@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// SafePathTrie wraps a PathTrie so that it can be used from multiple goroutines.
+// Mutations take an exclusive lock while lookups share a read lock, so readers
+// don't block each other.
+// The plain PathTrie takes no lock at all and remains the right choice for
+// single-threaded use.
+type SafePathTrie struct {
+	mu   sync.RWMutex
+	trie PathTrie
+}
+
+// NewSafe creates a SafePathTrie guarding pt. The caller must not access pt
+// directly afterward.
+func NewSafe(pt PathTrie) *SafePathTrie {
+	return &SafePathTrie{
+		trie: pt,
+	}
+}
+
+// InsertMerge is the concurrency-safe version of PathTrie.InsertMerge.
+func (spt *SafePathTrie) InsertMerge(path string, val any, merge ValueMergeFunc) bool {
+	spt.mu.Lock()
+	defer spt.mu.Unlock()
+	return spt.trie.InsertMerge(path, val, merge)
+}
+
+// TryInsertMerge is the concurrency-safe version of PathTrie.TryInsertMerge.
+func (spt *SafePathTrie) TryInsertMerge(path string, val any, merge ValueMergeFunc) (bool, error) {
+	spt.mu.Lock()
+	defer spt.mu.Unlock()
+	return spt.trie.TryInsertMerge(path, val, merge)
+}
+
+// Insert is the concurrency-safe version of PathTrie.Insert.
+func (spt *SafePathTrie) Insert(path string, val any) bool {
+	spt.mu.Lock()
+	defer spt.mu.Unlock()
+	return spt.trie.Insert(path, val)
+}
+
+// Delete is the concurrency-safe version of PathTrie.Delete.
+func (spt *SafePathTrie) Delete(path string) bool {
+	spt.mu.Lock()
+	defer spt.mu.Unlock()
+	return spt.trie.Delete(path)
+}
+
+// DeleteSubtree is the concurrency-safe version of PathTrie.DeleteSubtree.
+func (spt *SafePathTrie) DeleteSubtree(prefix string) int {
+	spt.mu.Lock()
+	defer spt.mu.Unlock()
+	return spt.trie.DeleteSubtree(prefix)
+}
+
+// Reset is the concurrency-safe version of PathTrie.Reset.
+func (spt *SafePathTrie) Reset() {
+	spt.mu.Lock()
+	defer spt.mu.Unlock()
+	spt.trie.Reset()
+}
+
+// GetValue is the concurrency-safe version of PathTrie.GetValue.
+func (spt *SafePathTrie) GetValue(path string) any {
+	spt.mu.RLock()
+	defer spt.mu.RUnlock()
+	return spt.trie.GetValue(path)
+}
+
+// GetValueContext is the concurrency-safe version of PathTrie.GetValueContext.
+func (spt *SafePathTrie) GetValueContext(ctx context.Context, path string) (any, error) {
+	spt.mu.RLock()
+	defer spt.mu.RUnlock()
+	return spt.trie.GetValueContext(ctx, path)
+}
+
+// GetPathAndValue is the concurrency-safe version of PathTrie.GetPathAndValue.
+func (spt *SafePathTrie) GetPathAndValue(path string) (string, any, bool) {
+	spt.mu.RLock()
+	defer spt.mu.RUnlock()
+	return spt.trie.GetPathAndValue(path)
+}
+
+// Touch is the concurrency-safe version of PathTrie.Touch. As Hits counters are
+// updated atomically, it only takes the read lock.
+func (spt *SafePathTrie) Touch(path string) {
+	spt.mu.RLock()
+	defer spt.mu.RUnlock()
+	spt.trie.Touch(path)
+}
+
+// GetChildren is the concurrency-safe version of PathTrie.GetChildren.
+func (spt *SafePathTrie) GetChildren() []string {
+	spt.mu.RLock()
+	defer spt.mu.RUnlock()
+	return spt.trie.GetChildren()
+}
+
+// Walk is the concurrency-safe version of PathTrie.Walk. The read lock is held
+// for the whole traversal, so fn must not call any of the SafePathTrie methods
+// that mutate the trie.
+func (spt *SafePathTrie) Walk(fn func(node *TrieNode) bool) {
+	spt.mu.RLock()
+	defer spt.mu.RUnlock()
+	spt.trie.Walk(fn)
+}
+
+// PathValue is a value-holding path of a PathTrie.
+type PathValue struct {
+	FullPath string
+	Value    any
+}
+
+// Snapshot returns the full path and value of every value-holding node, sorted
+// by full path. Unlike Walk, the read lock is only held while copying them, so
+// that callers can iterate over the result for as long as needed without
+// blocking writers. This costs a slice as large as the number of paths, and
+// values are copied by reference.
+func (spt *SafePathTrie) Snapshot() []PathValue {
+	spt.mu.RLock()
+	var snapshot []PathValue
+	for _, rootNode := range spt.trie.Trie {
+		walkAll(rootNode, func(node *TrieNode) bool {
+			snapshot = append(snapshot, PathValue{FullPath: node.FullPath, Value: node.Value})
+			return true
+		})
+	}
+	spt.mu.RUnlock()
+
+	slices.SortFunc(snapshot, func(a, b PathValue) int {
+		return strings.Compare(a.FullPath, b.FullPath)
+	})
+	return snapshot
+}
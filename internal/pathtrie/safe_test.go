@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSafePathTrie_concurrentAccess(t *testing.T) {
+	spt := NewSafe(New())
+
+	const writers = 8
+	const pathsPerWriter = 100
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < pathsPerWriter; i++ {
+				spt.Insert(fmt.Sprintf("/api/w%d/items/%d", w, i), i)
+			}
+		}(w)
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < pathsPerWriter; i++ {
+				_ = spt.GetChildren()
+				_ = spt.GetValue("/api/w0/items/0")
+				spt.Walk(func(*TrieNode) bool {
+					return true
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	for w := 0; w < writers; w++ {
+		for i := 0; i < pathsPerWriter; i++ {
+			path := fmt.Sprintf("/api/w%d/items/%d", w, i)
+			if got := spt.GetValue(path); got != i {
+				t.Fatalf("GetValue(%s) = %v, want %v", path, got, i)
+			}
+		}
+	}
+
+	if !spt.Delete("/api/w0/items/0") {
+		t.Errorf("Delete() = false, want true")
+	}
+	if _, _, found := spt.GetPathAndValue("/api/w0/items/0"); found {
+		t.Errorf("GetPathAndValue() found deleted path")
+	}
+	if got := spt.DeleteSubtree("/api/w1"); got != pathsPerWriter {
+		t.Errorf("DeleteSubtree() = %v, want %v", got, pathsPerWriter)
+	}
+}
+
+func TestSafePathTrie_Touch(t *testing.T) {
+	pt := New()
+	pt.Insert("/api/items/{id}", 1)
+	spt := NewSafe(pt)
+
+	const touchers = 8
+	const touches = 100
+
+	var wg sync.WaitGroup
+	for w := 0; w < touchers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < touches; i++ {
+				spt.Touch(fmt.Sprintf("/api/items/%d", i))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if got, want := pt.TopN(1)[0].Hits, uint64(touchers*touches); got != want {
+		t.Errorf("Hits = %v, want %v", got, want)
+	}
+}
+
+func TestSafePathTrie_Snapshot(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/users", value: 2},
+		pathAndValue{path: "/v1/orders/", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+	spt := NewSafe(pt)
+
+	snapshot := spt.Snapshot()
+	// Mutating the trie doesn't affect the snapshot.
+	spt.Insert("/v2/users", 4)
+	spt.Delete("/v1/users")
+
+	want := []PathValue{
+		{FullPath: "/v1/orders/", Value: 3},
+		{FullPath: "/v1/users", Value: 2},
+		{FullPath: "/v1/users/{id}", Value: 1},
+	}
+	if !reflect.DeepEqual(snapshot, want) {
+		t.Errorf("Snapshot() = %v, want %v", snapshot, want)
+	}
+}
+
+func TestSafePathTrie_Snapshot_concurrentInsert(t *testing.T) {
+	spt := NewSafe(New())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			spt.Insert(fmt.Sprintf("/api/items/%d", i), i)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		for _, pv := range spt.Snapshot() {
+			if pv.Value == nil {
+				t.Fatalf("Snapshot() returned a nil value for %s", pv.FullPath)
+			}
+		}
+	}
+	wg.Wait()
+
+	if got := len(spt.Snapshot()); got != 1000 {
+		t.Errorf("len(Snapshot()) = %v, want 1000", got)
+	}
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"strconv"
+)
+
+// EndpointStats counts the requests observed for a path, by HTTP method and by
+// response status code, e.g. {"GET": 3} and {"200": 2, "404": 1}. It is meant
+// to be inserted with MergeEndpointStats, see StatsFor.
+type EndpointStats struct {
+	MethodCounts map[string]int
+	StatusCounts map[string]int
+}
+
+// EndpointStatsReporter is implemented by the operations of a map[string]any
+// value keyed by uppercased HTTP method, e.g. trafficloader.Operation, so that
+// StatsFor can aggregate their counts.
+type EndpointStatsReporter interface {
+	EndpointStats() EndpointStats
+}
+
+// NewEndpointStats returns the EndpointStats of a single request.
+func NewEndpointStats(method string, status int) EndpointStats {
+	return EndpointStats{
+		MethodCounts: map[string]int{method: 1},
+		StatusCounts: map[string]int{strconv.Itoa(status): 1},
+	}
+}
+
+// MergeEndpointStats is a ValueMergeFunc adding the counts of the new
+// EndpointStats to the existing one, which is overwritten if it isn't an
+// EndpointStats. The counts are written to new maps, as the EndpointStats first
+// inserted at a path is stored as is and may be shared, e.g. by several paths.
+func MergeEndpointStats(existing, newV *any) {
+	stats, ok := (*existing).(EndpointStats)
+	newStats, newOK := (*newV).(EndpointStats)
+	if !ok || !newOK {
+		*existing = *newV
+		return
+	}
+
+	*existing = addEndpointStats(stats, newStats)
+}
+
+// addEndpointStats returns the sum of a and b, without modifying their maps.
+func addEndpointStats(a, b EndpointStats) EndpointStats {
+	return EndpointStats{
+		MethodCounts: addCounts(a.MethodCounts, b.MethodCounts),
+		StatusCounts: addCounts(a.StatusCounts, b.StatusCounts),
+	}
+}
+
+func addCounts(a, b map[string]int) map[string]int {
+	counts := make(map[string]int, max(len(a), len(b)))
+	for key, count := range a {
+		counts[key] += count
+	}
+	for key, count := range b {
+		counts[key] += count
+	}
+	return counts
+}
+
+// StatsFor resolves path like GetValue and returns the EndpointStats of the
+// matched node, and false if no node matched or its value holds no stats. The
+// value is either an EndpointStats or, like for MatchOperation, a
+// map[string]any of operations by method, such as the tries built by
+// trafficloader, whose EndpointStatsReporter operations are summed. The
+// returned maps are copies.
+func (pt *PathTrie) StatsFor(path string) (EndpointStats, bool) {
+	node := pt.getNode(path)
+	if node == nil {
+		return EndpointStats{}, false
+	}
+
+	switch value := node.Value.(type) {
+	case EndpointStats:
+		return addEndpointStats(value, EndpointStats{}), true
+	case map[string]any:
+		var stats EndpointStats
+		found := false
+		for _, operation := range value {
+			reporter, ok := operation.(EndpointStatsReporter)
+			if !ok {
+				continue
+			}
+			stats = addEndpointStats(stats, reporter.EndpointStats())
+			found = true
+		}
+		return stats, found
+	}
+
+	return EndpointStats{}, false
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathTrie_StatsFor(t *testing.T) {
+	pt := New()
+	for _, observation := range []struct {
+		method string
+		path   string
+		status int
+	}{
+		{method: "GET", path: "/v1/users/{id}", status: 200},
+		{method: "GET", path: "/v1/users/{id}", status: 404},
+		{method: "DELETE", path: "/v1/users/{id}", status: 204},
+		{method: "GET", path: "/v1/users/{id}", status: 200},
+		{method: "GET", path: "/v1/users", status: 200},
+	} {
+		pt.InsertMerge(observation.path, NewEndpointStats(observation.method, observation.status), MergeEndpointStats)
+	}
+	pt.Insert("/v1/orders", "not stats")
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   EndpointStats
+		wantOK bool
+	}{
+		{
+			name: "several observations",
+			args: args{
+				path: "/v1/users/42",
+			},
+			want: EndpointStats{
+				MethodCounts: map[string]int{"GET": 3, "DELETE": 1},
+				StatusCounts: map[string]int{"200": 2, "404": 1, "204": 1},
+			},
+			wantOK: true,
+		},
+		{
+			name: "single observation",
+			args: args{
+				path: "/v1/users",
+			},
+			want: EndpointStats{
+				MethodCounts: map[string]int{"GET": 1},
+				StatusCounts: map[string]int{"200": 1},
+			},
+			wantOK: true,
+		},
+		{
+			name: "not stats",
+			args: args{
+				path: "/v1/orders",
+			},
+			want:   EndpointStats{},
+			wantOK: false,
+		},
+		{
+			name: "no match",
+			args: args{
+				path: "/v2",
+			},
+			want:   EndpointStats{},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pt.StatsFor(tt.args.path)
+			if !reflect.DeepEqual(got, tt.want) || ok != tt.wantOK {
+				t.Errorf("StatsFor() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	// The returned stats are a copy.
+	stats, _ := pt.StatsFor("/v1/users")
+	stats.MethodCounts["GET"] = 100
+	if got, _ := pt.StatsFor("/v1/users"); got.MethodCounts["GET"] != 1 {
+		t.Errorf("StatsFor() = %v after modifying a copy, want 1 GET", got.MethodCounts)
+	}
+}
+
+func TestMergeEndpointStats(t *testing.T) {
+	var existing any = EndpointStats{}
+	var newV any = NewEndpointStats("POST", 201)
+	MergeEndpointStats(&existing, &newV)
+	MergeEndpointStats(&existing, &newV)
+
+	want := EndpointStats{
+		MethodCounts: map[string]int{"POST": 2},
+		StatusCounts: map[string]int{"201": 2},
+	}
+	if !reflect.DeepEqual(existing, want) {
+		t.Errorf("MergeEndpointStats() = %v, want %v", existing, want)
+	}
+
+	existing = "other"
+	MergeEndpointStats(&existing, &newV)
+	if !reflect.DeepEqual(existing, newV) {
+		t.Errorf("MergeEndpointStats() = %v, want %v", existing, newV)
+	}
+}
+
+func TestMergeEndpointStats_sharedValue(t *testing.T) {
+	pt := New()
+	stats := NewEndpointStats("GET", 200)
+	pt.InsertMerge("/a", stats, MergeEndpointStats)
+	pt.InsertMerge("/b", stats, MergeEndpointStats)
+	pt.InsertMerge("/a", stats, MergeEndpointStats)
+
+	single := NewEndpointStats("GET", 200)
+	if got, _ := pt.StatsFor("/a"); !reflect.DeepEqual(got, EndpointStats{
+		MethodCounts: map[string]int{"GET": 2},
+		StatusCounts: map[string]int{"200": 2},
+	}) {
+		t.Errorf("StatsFor(/a) = %v, want 2 GET 200", got)
+	}
+	if got, _ := pt.StatsFor("/b"); !reflect.DeepEqual(got, single) {
+		t.Errorf("StatsFor(/b) = %v, want %v", got, single)
+	}
+	if !reflect.DeepEqual(stats, single) {
+		t.Errorf("inserted value = %v after merging, want %v", stats, single)
+	}
+}
+
+type reporterStub struct {
+	stats EndpointStats
+}
+
+func (r reporterStub) EndpointStats() EndpointStats {
+	return r.stats
+}
+
+func TestPathTrie_StatsFor_operations(t *testing.T) {
+	pt := New()
+	pt.Insert("/v1/users", map[string]any{
+		"GET":  reporterStub{stats: NewEndpointStats("GET", 200)},
+		"POST": reporterStub{stats: NewEndpointStats("POST", 200)},
+	})
+	pt.Insert("/v1/orders", map[string]any{"GET": "not stats"})
+
+	want := EndpointStats{
+		MethodCounts: map[string]int{"GET": 1, "POST": 1},
+		StatusCounts: map[string]int{"200": 2},
+	}
+	if got, ok := pt.StatsFor("/v1/users"); !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("StatsFor() = %v, %v, want %v, true", got, ok, want)
+	}
+	if got, ok := pt.StatsFor("/v1/orders"); ok {
+		t.Errorf("StatsFor() = %v, %v, want false", got, ok)
+	}
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+// ConsumePaths inserts the items received from ch like Insert until ch is
+// closed, so that producers are slowed down to the insertion rate instead of
+// buffering their paths. A repeated path overwrites the existing value, see
+// ConsumePathsMerge to combine them instead, e.g. to count hits.
+// It returns the first insertion error, see TryInsertMerge. The following
+// items are not inserted, but ch is still drained until closed, so that
+// producers blocked on send aren't leaked.
+func (pt *PathTrie) ConsumePaths(ch <-chan PathValue) error {
+	return pt.ConsumePathsMerge(ch, nil)
+}
+
+// ConsumePathsMerge is like ConsumePaths but inserts the items with InsertMerge,
+// so that repeated paths are combined with merge, e.g. a merge function adding
+// ints counts the hits of paths sent with a value of 1. A nil merge overwrites
+// the existing values like ConsumePaths.
+func (pt *PathTrie) ConsumePathsMerge(ch <-chan PathValue, merge ValueMergeFunc) error {
+	return consumePaths(ch, merge, pt.TryInsertMerge)
+}
+
+// ConsumePaths is the concurrency-safe version of PathTrie.ConsumePaths. The
+// lock is taken for each item, so that other goroutines can query the trie
+// while it is being built.
+func (spt *SafePathTrie) ConsumePaths(ch <-chan PathValue) error {
+	return spt.ConsumePathsMerge(ch, nil)
+}
+
+// ConsumePathsMerge is the concurrency-safe version of
+// PathTrie.ConsumePathsMerge, locking like ConsumePaths.
+func (spt *SafePathTrie) ConsumePathsMerge(ch <-chan PathValue, merge ValueMergeFunc) error {
+	return consumePaths(ch, merge, spt.TryInsertMerge)
+}
+
+// consumePaths inserts the items of ch with insert until the first error, then
+// drains ch.
+func consumePaths(ch <-chan PathValue, merge ValueMergeFunc, insert func(string, any, ValueMergeFunc) (bool, error)) error {
+	merge = mergeOrOverwrite(merge)
+	for item := range ch {
+		if _, err := insert(item.FullPath, item.Value, merge); err != nil {
+			for range ch {
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeOrOverwrite returns merge, or a function overwriting the existing value
+// if it is nil.
+func mergeOrOverwrite(merge ValueMergeFunc) ValueMergeFunc {
+	if merge != nil {
+		return merge
+	}
+
+	return func(existing, newV *any) {
+		*existing = *newV
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func countHits(existing, newV *any) {
+	count, _ := (*existing).(int)
+	*existing = count + (*newV).(int)
+}
+
+func TestPathTrie_ConsumePaths(t *testing.T) {
+	type args struct {
+		items []PathValue
+		merge ValueMergeFunc
+	}
+	tests := []struct {
+		name string
+		args args
+		want map[string]any
+	}{
+		{
+			name: "hit counting",
+			args: args{
+				items: []PathValue{
+					{FullPath: "/v1/users", Value: 1},
+					{FullPath: "/v1/users/{id}", Value: 1},
+					{FullPath: "/v1/users", Value: 1},
+				},
+				merge: countHits,
+			},
+			want: map[string]any{"/v1/users": 2, "/v1/users/{id}": 1},
+		},
+		{
+			name: "nil merge overwrites",
+			args: args{
+				items: []PathValue{
+					{FullPath: "/v1/users", Value: 1},
+					{FullPath: "/v1/users", Value: 3},
+				},
+			},
+			want: map[string]any{"/v1/users": 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan PathValue)
+			go func() {
+				defer close(ch)
+				for _, item := range tt.args.items {
+					ch <- item
+				}
+			}()
+
+			pt := New()
+			if err := pt.ConsumePathsMerge(ch, tt.args.merge); err != nil {
+				t.Fatalf("ConsumePaths() error = %v", err)
+			}
+			for path, want := range tt.want {
+				if got := pt.GetValue(path); got != want {
+					t.Errorf("GetValue(%s) = %v, want %v", path, got, want)
+				}
+			}
+			if got := pt.Size(); got != len(tt.want) {
+				t.Errorf("Size() = %v, want %v", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSafePathTrie_ConsumePaths(t *testing.T) {
+	spt := NewSafe(New())
+	const paths = 1000
+
+	ch := make(chan PathValue, 16)
+	go func() {
+		defer close(ch)
+		for i := 0; i < paths; i++ {
+			ch <- PathValue{FullPath: fmt.Sprintf("/api/items/%d", i%100), Value: 1}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < paths; i++ {
+			_ = spt.GetValue("/api/items/0")
+		}
+	}()
+
+	if err := spt.ConsumePathsMerge(ch, countHits); err != nil {
+		t.Fatalf("ConsumePaths() error = %v", err)
+	}
+	wg.Wait()
+
+	for i := 0; i < 100; i++ {
+		path := fmt.Sprintf("/api/items/%d", i)
+		if got := spt.GetValue(path); got != 10 {
+			t.Errorf("GetValue(%s) = %v, want %v", path, got, 10)
+		}
+	}
+}
+
+func TestPathTrie_ConsumePaths_error(t *testing.T) {
+	pt := New(WithMaxDepth(2))
+	ch := make(chan PathValue)
+	sent := make(chan int)
+	go func() {
+		count := 0
+		for _, path := range []string{"/v1/users", "/v1/users/{id}/posts", "/v1/orders", "/v1"} {
+			ch <- PathValue{FullPath: path, Value: 1}
+			count++
+		}
+		close(ch)
+		sent <- count
+	}()
+
+	if err := pt.ConsumePaths(ch); !errors.Is(err, ErrMaxDepthExceeded) {
+		t.Errorf("ConsumePaths() error = %v, want %v", err, ErrMaxDepthExceeded)
+	}
+	if count := <-sent; count != 4 {
+		t.Errorf("producer sent %v items, want 4", count)
+	}
+	if got := pt.Size(); got != 1 {
+		t.Errorf("Size() = %v, want 1", got)
+	}
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"fmt"
+)
+
+// GetValueStrict is like GetValueOK but fails closed: it returns
+// ErrAmbiguousMatch rather than picking a node when the most accurate matching
+// nodes are tied, i.e. none is an exact match and they have as many path params,
+// e.g. /a/{x}/c and /a/b/{y} for /a/b/c. ErrPathNotFound is returned if no node
+// matches. See also StrictMatch.
+func (pt *PathTrie) GetValueStrict(path string) (any, error) {
+	if pt.PathSeparator == "" {
+		return nil, ErrEmptySeparator
+	}
+
+	segments := pt.splitPath(path)
+	nodes := pt.getMatches(segments)
+	ambiguous := pt.isAmbiguous(nodes, segments)
+	if pt.Metrics != nil {
+		pt.Metrics.IncMatch(len(nodes) > 0 && !ambiguous)
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: `%s`", ErrPathNotFound, path)
+	}
+	if ambiguous {
+		return nil, fmt.Errorf("%w: `%s` matches both `%s` and `%s`", ErrAmbiguousMatch, path,
+			nodes[0].FullPath, nodes[1].FullPath)
+	}
+
+	return nodes[0].Value, nil
+}
+
+// isAmbiguous reports whether the most accurate of nodes, as sorted by
+// getMatches, is tied with the next one.
+func (pt *PathTrie) isAmbiguous(nodes []*TrieNode, segments []string) bool {
+	if len(nodes) < 2 || pt.isFullPathSegments(nodes[0].FullPath, segments) {
+		return false
+	}
+
+	return nodes[0].isCatchAll() == nodes[1].isCatchAll() && nodes[0].PathParamCounter == nodes[1].PathParamCounter
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPathTrie_GetValueStrict(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/a/{x}/c", value: 1},
+		pathAndValue{path: "/a/b/{y}", value: 2},
+		pathAndValue{path: "/a/b/c", value: 3},
+		pathAndValue{path: "/d/{x}/{y}", value: 4},
+		pathAndValue{path: "/d/e/{y}", value: 5},
+		pathAndValue{path: "/f/**", value: 6},
+		pathAndValue{path: "/f/{x}", value: 7},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    any
+		wantErr error
+	}{
+		{
+			name: "single match",
+			args: args{
+				path: "/a/b/z",
+			},
+			want: 2,
+		},
+		{
+			name: "exact match among tied nodes",
+			args: args{
+				path: "/a/b/c",
+			},
+			want: 3,
+		},
+		{
+			name: "fewer path params",
+			args: args{
+				path: "/d/e/f",
+			},
+			want: 5,
+		},
+		{
+			name: "catch-all is less accurate",
+			args: args{
+				path: "/f/g",
+			},
+			want: 7,
+		},
+		{
+			name: "no match",
+			args: args{
+				path: "/g",
+			},
+			wantErr: ErrPathNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pt.GetValueStrict(tt.args.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetValueStrict() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetValueStrict() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_GetValueStrict_ambiguous(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/a/{x}/c", value: 1},
+		pathAndValue{path: "/a/b/{y}", value: 2},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := pt.GetValueStrict("/a/b/c")
+	if !errors.Is(err, ErrAmbiguousMatch) {
+		t.Fatalf("GetValueStrict() error = %v, want %v", err, ErrAmbiguousMatch)
+	}
+	if want := "ambiguous match: `/a/b/c` matches both `/a/b/{y}` and `/a/{x}/c`"; err.Error() != want {
+		t.Errorf("GetValueStrict() error = %v, want %v", err, want)
+	}
+
+	// Best effort by default.
+	if got := pt.GetValue("/a/b/c"); got != 2 {
+		t.Errorf("GetValue() = %v, want 2", got)
+	}
+
+	pt.StrictMatch = true
+	if got, ok := pt.GetValueOK("/a/b/c"); ok {
+		t.Errorf("GetValueOK() = %v, want no match with StrictMatch", got)
+	}
+	if got := pt.GetValue("/a/x/c"); got != 1 {
+		t.Errorf("GetValue() = %v, want 1 with StrictMatch", got)
+	}
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+// TypedPathTrie is a PathTrie holding values of type T, so that callers don't
+// need type assertions on the values it returns. Values are stored as any in the
+// underlying PathTrie, which means that a nil interface or pointer value of type
+// T is treated like a missing value.
+type TypedPathTrie[T any] struct {
+	trie PathTrie
+}
+
+// TypedValueMergeFunc is the typed counterpart of ValueMergeFunc. existing holds
+// the zero value of T when the path was not holding a value yet.
+type TypedValueMergeFunc[T any] func(existing, newV *T)
+
+// NewTypedWithPathSeparator creates a TypedPathTrie with a user-supplied path
+// separator.
+func NewTypedWithPathSeparator[T any](pathSeparator string) *TypedPathTrie[T] {
+	return &TypedPathTrie[T]{
+		trie: NewWithPathSeparator(pathSeparator),
+	}
+}
+
+// NewTyped creates a TypedPathTrie with "/" as the path separator.
+func NewTyped[T any]() *TypedPathTrie[T] {
+	return NewTypedWithPathSeparator[T]("/")
+}
+
+// InsertMerge is the typed version of PathTrie.InsertMerge.
+func (tpt *TypedPathTrie[T]) InsertMerge(path string, val T, merge TypedValueMergeFunc[T]) bool {
+	return tpt.trie.InsertMerge(path, val, func(existing, newV *any) {
+		existingVal, _ := (*existing).(T)
+		newVal, _ := (*newV).(T)
+		merge(&existingVal, &newVal)
+		*existing = existingVal
+	})
+}
+
+// Insert is the typed version of PathTrie.Insert.
+func (tpt *TypedPathTrie[T]) Insert(path string, val T) bool {
+	return tpt.trie.Insert(path, val)
+}
+
+// GetValue returns the given node path value, and false if node is not found.
+func (tpt *TypedPathTrie[T]) GetValue(path string) (T, bool) {
+	_, val, found := tpt.GetPathAndValue(path)
+	return val, found
+}
+
+// GetPathAndValue is the typed version of PathTrie.GetPathAndValue.
+func (tpt *TypedPathTrie[T]) GetPathAndValue(path string) (string, T, bool) {
+	fullPath, val, found := tpt.trie.GetPathAndValue(path)
+	if !found {
+		var zero T
+		return "", zero, false
+	}
+
+	return fullPath, val.(T), true
+}
+
+// Delete is the typed version of PathTrie.Delete.
+func (tpt *TypedPathTrie[T]) Delete(path string) bool {
+	return tpt.trie.Delete(path)
+}
+
+// GetChildren is the typed version of PathTrie.GetChildren.
+func (tpt *TypedPathTrie[T]) GetChildren() []string {
+	return tpt.trie.GetChildren()
+}
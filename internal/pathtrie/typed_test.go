@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type callStats struct {
+	Calls  int
+	Errors int
+}
+
+func TestTypedPathTrie(t *testing.T) {
+	tpt := NewTyped[callStats]()
+	sumMerge := func(existing, newV *callStats) {
+		existing.Calls += newV.Calls
+		existing.Errors += newV.Errors
+	}
+
+	if !tpt.Insert("/v1/users/{id}", callStats{Calls: 1}) {
+		t.Errorf("Insert() = false, want true")
+	}
+	if tpt.InsertMerge("/v1/users/{id}", callStats{Calls: 2, Errors: 1}, sumMerge) {
+		t.Errorf("InsertMerge() = true, want false")
+	}
+	// The intermediate /v1/users node has no value yet.
+	if !tpt.InsertMerge("/v1/users", callStats{Calls: 3}, sumMerge) {
+		t.Errorf("InsertMerge() = false, want true")
+	}
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantPath  string
+		wantValue callStats
+		wantFound bool
+	}{
+		{
+			name: "merged value",
+			args: args{
+				path: "/v1/users/42",
+			},
+			wantPath:  "/v1/users/{id}",
+			wantValue: callStats{Calls: 3, Errors: 1},
+			wantFound: true,
+		},
+		{
+			name: "merged into intermediate node",
+			args: args{
+				path: "/v1/users",
+			},
+			wantPath:  "/v1/users",
+			wantValue: callStats{Calls: 3},
+			wantFound: true,
+		},
+		{
+			name: "no match",
+			args: args{
+				path: "/v1/orders",
+			},
+			wantPath:  "",
+			wantValue: callStats{},
+			wantFound: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath, gotValue, gotFound := tpt.GetPathAndValue(tt.args.path)
+			if gotPath != tt.wantPath {
+				t.Errorf("GetPathAndValue() gotPath = %v, wantPath %v", gotPath, tt.wantPath)
+			}
+			if gotValue != tt.wantValue {
+				t.Errorf("GetPathAndValue() gotValue = %v, wantValue %v", gotValue, tt.wantValue)
+			}
+			if gotFound != tt.wantFound {
+				t.Errorf("GetPathAndValue() gotFound = %v, wantFound %v", gotFound, tt.wantFound)
+			}
+
+			gotValue, gotFound = tpt.GetValue(tt.args.path)
+			if gotValue != tt.wantValue || gotFound != tt.wantFound {
+				t.Errorf("GetValue() = (%v, %v), want (%v, %v)", gotValue, gotFound, tt.wantValue, tt.wantFound)
+			}
+		})
+	}
+
+	if !tpt.Delete("/v1/users/{id}") {
+		t.Errorf("Delete() = false, want true")
+	}
+	got := tpt.GetChildren()
+	sort.Strings(got)
+	if want := []string{"/v1", "/v1/users"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetChildren() = %v, want %v", got, want)
+	}
+}
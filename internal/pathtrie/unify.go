@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"slices"
+	"strings"
+)
+
+// genericParam returns the name of the path param nodes that don't stem from a
+// single inserted segment: CompactedParam, or Wildcard if IsPathParam doesn't
+// recognize it or if it contains the separator.
+func (pt *PathTrie) genericParam() string {
+	if !pt.isPathParam(CompactedParam) || strings.Contains(CompactedParam, pt.PathSeparator) {
+		return Wildcard
+	}
+
+	return CompactedParam
+}
+
+// isUnifiable reports whether segment is a path param stored under the generic
+// param, see UnifyPathParams. Nothing is if the generic param contains the
+// separator.
+func (pt *PathTrie) isUnifiable(segment string) bool {
+	return pt.UnifyPathParams && segment != Wildcard && segment != CatchAll && pt.isPathParam(segment) &&
+		!strings.Contains(pt.genericParam(), pt.PathSeparator)
+}
+
+// unifyParam returns the segment under which segment is stored, which is the
+// generic param for unifiable path params, or segment itself.
+func (pt *PathTrie) unifyParam(segment string) string {
+	if !pt.isUnifiable(segment) {
+		return segment
+	}
+
+	return pt.genericParam()
+}
+
+// keepParamName records the original name of a path param unified into node if
+// KeepParamNames is set.
+func (pt *PathTrie) keepParamName(node *TrieNode, name string) {
+	if !pt.KeepParamNames || !pt.isUnifiable(name) {
+		return
+	}
+	if !slices.Contains(node.ParamNames, name) {
+		node.ParamNames = append(node.ParamNames, name)
+	}
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPathTrie_UnifyPathParams(t *testing.T) {
+	pt := New()
+	pt.UnifyPathParams = true
+	pt.KeepParamNames = true
+
+	appendSpecs := func(existing, newV *any) {
+		specs, _ := (*existing).([]string)
+		*existing = append(specs, (*newV).([]string)...)
+	}
+	if !pt.InsertMerge("/v1/users/{userId}/posts/{postId}", []string{"users"}, appendSpecs) {
+		t.Fatalf("InsertMerge() = false, want true")
+	}
+	if pt.InsertMerge("/v1/users/{id}/posts/{pid}", []string{"posts"}, appendSpecs) {
+		t.Errorf("InsertMerge() = true, want false for the same route")
+	}
+
+	gotChildren := pt.GetChildren()
+	sort.Strings(gotChildren)
+	wantChildren := []string{
+		"/v1", "/v1/users", "/v1/users/{param}", "/v1/users/{param}/posts", "/v1/users/{param}/posts/{param}",
+	}
+	if !reflect.DeepEqual(gotChildren, wantChildren) {
+		t.Errorf("GetChildren() = %v, want %v", gotChildren, wantChildren)
+	}
+
+	node, exact, ok := pt.MatchDetail("/v1/users/42/posts/7")
+	if !ok || exact {
+		t.Fatalf("MatchDetail() = %v, %v, %v, want a templated match", node, exact, ok)
+	}
+	if want := []string{"users", "posts"}; !reflect.DeepEqual(node.Value, want) {
+		t.Errorf("Value = %v, want %v", node.Value, want)
+	}
+	if want := []string{"{postId}", "{pid}"}; !reflect.DeepEqual(node.ParamNames, want) {
+		t.Errorf("ParamNames = %v, want %v", node.ParamNames, want)
+	}
+
+	// Wildcards keep their meaning.
+	pt.Insert("/v1/static/**", "static")
+	if got := pt.GetValue("/v1/static/css/app.css"); got != "static" {
+		t.Errorf("GetValue() = %v, want %v", got, "static")
+	}
+
+	if !pt.Delete("/v1/users/{uid}/posts/{id}") {
+		t.Errorf("Delete() = false, want true")
+	}
+	if got := pt.GetValue("/v1/users/42/posts/7"); got != nil {
+		t.Errorf("GetValue() = %v, want %v", got, nil)
+	}
+}
+
+func TestPathTrie_UnifyPathParams_customParams(t *testing.T) {
+	pt := New()
+	pt.UnifyPathParams = true
+	pt.IsPathParam = func(segment string) bool {
+		return len(segment) > 1 && segment[0] == ':'
+	}
+	pt.Insert("/v1/users/:userId", 1)
+	pt.Insert("/v1/users/:id", 2)
+
+	gotChildren := pt.GetChildren()
+	sort.Strings(gotChildren)
+	if wantChildren := []string{"/v1", "/v1/users", "/v1/users/*"}; !reflect.DeepEqual(gotChildren, wantChildren) {
+		t.Errorf("GetChildren() = %v, want %v", gotChildren, wantChildren)
+	}
+	if got := pt.GetValue("/v1/users/42"); got != 2 {
+		t.Errorf("GetValue() = %v, want %v", got, 2)
+	}
+}
+
+func TestPathTrie_UnifyPathParams_InsertBatch(t *testing.T) {
+	pt := New()
+	pt.UnifyPathParams = true
+	pt.KeepParamNames = true
+	if err := pt.InsertBatch([]string{"/v1/users/{id}/posts", "/v1/users/{userId}/posts/{postId}"}, []any{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	node, _, ok := pt.MatchDetail("/v1/users/{param}/posts")
+	if !ok {
+		t.Fatalf("MatchDetail() ok = false, want true")
+	}
+	users := pt.Trie[""].Children["v1"].Children["users"].Children[CompactedParam]
+	if want := []string{"{id}", "{userId}"}; !reflect.DeepEqual(users.ParamNames, want) {
+		t.Errorf("ParamNames = %v, want %v", users.ParamNames, want)
+	}
+	if node.Value != 1 {
+		t.Errorf("Value = %v, want %v", node.Value, 1)
+	}
+}
+
+func TestPathTrie_UnifyPathParams_separatorInGenericParam(t *testing.T) {
+	// CompactedParam contains the separator, so Wildcard is used instead.
+	pt := NewWithPathSeparator("a")
+	pt.UnifyPathParams = true
+	pt.Insert("v1a{id}", 1)
+
+	if got := pt.GetChildren(); !reflect.DeepEqual(got, []string{"v1", "v1a*"}) {
+		t.Errorf("GetChildren() = %v, want [v1 v1a*]", got)
+	}
+
+	// Wildcard is the separator too, so path params aren't unified.
+	pt = NewWithPathSeparator("*")
+	pt.UnifyPathParams = true
+	pt.Insert("v1*{id}", 1)
+
+	if got := pt.GetValue("v1*42"); got != 1 {
+		t.Errorf("GetValue() = %v, want 1", got)
+	}
+	if _, _, ok := pt.MatchDetail("v1*{id}"); !ok {
+		t.Errorf("MatchDetail() found no node for v1*{id}")
+	}
+}
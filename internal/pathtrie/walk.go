@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"slices"
+	"strings"
+)
+
+// Walk performs a depth-first traversal of the PathTrie, calling fn for every
+// value-holding node, including the root path and the paths ending with a
+// separator, which are held by empty-name marker children. All, Size,
+// GetChildren and GetChildrenToDepth list these marker children the same way.
+// The traversal stops as soon as fn returns false. Siblings are visited in map
+// iteration order, so the traversal order is not stable across calls.
+func (pt *PathTrie) Walk(fn func(node *TrieNode) bool) {
+	for node := range pt.nodes(0) {
+		if node.Value != nil && !fn(node) {
+			return
+		}
+	}
+}
+
+// walkAll calls fn for node and its descendants holding a value, including the
+// empty-name marker children.
+func walkAll(node *TrieNode, fn func(node *TrieNode) bool) bool {
+	if node.Value != nil && !fn(node) {
+		return false
+	}
+
+	for _, childNode := range node.Children {
+		if !walkAll(childNode, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetChildrenOf returns the full paths of all value-holding descendants of the
+// node at prefix, including the ones ending with a separator, but not the
+// prefix itself nor the prefix followed by a separator. The prefix is resolved
+// with param-aware matching, so /v1/tenants/acme lists the descendants of
+// /v1/tenants/{tenant} unless a more accurate static node exists. A trailing
+// separator of prefix is ignored, so that / lists every absolute path but the
+// root path. An empty slice is returned if prefix doesn't exist.
+func (pt *PathTrie) GetChildrenOf(prefix string) []string {
+	children := []string{}
+
+	segments := pt.splitPath(prefix)
+	if last := len(segments) - 1; last > 0 && segments[last] == "" {
+		segments = segments[:last]
+	}
+	node := pt.getPrefixNodeSegments(segments)
+	if node == nil {
+		return children
+	}
+	// A compressed node reached within stands for a shallower node, so that its
+	// own marker child is a descendant of prefix.
+	if strings.Count(node.FullPath, pt.PathSeparator)+1 > len(segments) {
+		walkAll(node, func(node *TrieNode) bool {
+			children = append(children, node.FullPath)
+			return true
+		})
+		return children
+	}
+
+	for childName, childNode := range node.Children {
+		if childName == "" {
+			continue
+		}
+		walkAll(childNode, func(node *TrieNode) bool {
+			children = append(children, node.FullPath)
+			return true
+		})
+	}
+
+	return children
+}
+
+// PathsWithValue returns the sorted full paths of every value-holding node,
+// including the ones ending with a separator, whose value satisfies match.
+func (pt *PathTrie) PathsWithValue(match func(val any) bool) []string {
+	return pt.pathsWithNode(func(node *TrieNode) bool {
+		return match(node.Value)
+	})
+}
+
+// Reduce folds fn over the values of the node at prefix and of its value-holding
+// descendants, including the ones ending with a separator, in FullPath order,
+// starting from seed, e.g. to sum the hit counts under a prefix. The prefix is
+// resolved like in GetChildrenOf. seed is returned if prefix doesn't exist.
+func (pt *PathTrie) Reduce(prefix string, seed any, fn func(acc, val any) any) any {
+	node := pt.getPrefixNode(prefix)
+	if node == nil {
+		return seed
+	}
+
+	var nodes []*TrieNode
+	walkAll(node, func(node *TrieNode) bool {
+		nodes = append(nodes, node)
+		return true
+	})
+	slices.SortFunc(nodes, func(a, b *TrieNode) int {
+		return strings.Compare(a.FullPath, b.FullPath)
+	})
+
+	acc := seed
+	for _, node := range nodes {
+		acc = fn(acc, node.Value)
+	}
+
+	return acc
+}
+
+// TemplatedPaths returns the sorted full paths of the value-holding nodes with at
+// least one path param segment, including the CatchAll one.
+func (pt *PathTrie) TemplatedPaths() []string {
+	return pt.pathsWithNode(func(node *TrieNode) bool {
+		return node.PathParamCounter > 0
+	})
+}
+
+// LiteralPaths returns the sorted full paths of the value-holding nodes without
+// any path param segment.
+func (pt *PathTrie) LiteralPaths() []string {
+	return pt.pathsWithNode(func(node *TrieNode) bool {
+		return node.PathParamCounter == 0
+	})
+}
+
+// pathsWithNode returns the sorted full paths of the value-holding nodes for
+// which match returns true.
+func (pt *PathTrie) pathsWithNode(match func(node *TrieNode) bool) []string {
+	paths := []string{}
+	for _, rootNode := range pt.Trie {
+		walkAll(rootNode, func(node *TrieNode) bool {
+			if match(node) {
+				paths = append(paths, node.FullPath)
+			}
+			return true
+		})
+	}
+	slices.Sort(paths)
+
+	return paths
+}
+
+// RootSegments returns the sorted distinct names of the first segments of the
+// paths, e.g. [orders users] for /users/{id} and /orders, without walking the
+// rest of the PathTrie. The empty leading segment of absolute paths and the
+// empty-name marker children are skipped.
+func (pt *PathTrie) RootSegments() []string {
+	var names []string
+	add := func(trie PathToTrieNode) {
+		for key, node := range trie {
+			if key == "" {
+				continue
+			}
+			// Only keep the first segment of compressed nodes.
+			name, _, _ := strings.Cut(node.Name, pt.PathSeparator)
+			names = append(names, name)
+		}
+	}
+
+	add(pt.Trie)
+	if root, ok := pt.Trie[""]; ok {
+		add(root.Children)
+	}
+	slices.Sort(names)
+
+	return slices.Compact(names)
+}
+
+// Size returns the number of value-holding paths visited by Walk, i.e. yielded
+// by All.
+func (pt *PathTrie) Size() int {
+	size := 0
+	pt.Walk(func(*TrieNode) bool {
+		size++
+		return true
+	})
+	return size
+}
+
+// NodeCount returns the total number of nodes in the PathTrie, including
+// intermediate nodes that don't hold a value.
+func (pt *PathTrie) NodeCount() int {
+	count := 0
+	for _, rootNode := range pt.Trie {
+		count += countNodes(rootNode)
+	}
+	return count
+}
+
+func countNodes(node *TrieNode) int {
+	count := 1
+	for _, child := range node.Children {
+		count += countNodes(child)
+	}
+	return count
+}
+
+// ChildrenNames returns the sorted names of the children of node, without the
+// empty-name marker children that hold the value of paths ending with a
+// separator. Along with Child, it allows traversing the PathTrie from the nodes
+// of PathTrie.Trie, e.g. Trie[""] for absolute paths.
+func (node *TrieNode) ChildrenNames() []string {
+	names := make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		if child.Name != "" {
+			names = append(names, child.Name)
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Child returns the child of node named name, as returned by ChildrenNames, or
+// nil if there is none. The empty-name marker child is never returned.
+func (node *TrieNode) Child(name string) *TrieNode {
+	if name == "" {
+		return nil
+	}
+	if child, ok := node.Children[name]; ok && child.Name == name {
+		return child
+	}
+
+	// Keys differ from names with e.g. CaseInsensitive or UnifyPathParams.
+	for _, child := range node.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+
+	return nil
+}
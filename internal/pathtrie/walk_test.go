@@ -0,0 +1,522 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package pathtrie
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPathTrie_Walk(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/api/items", value: 1},
+		pathAndValue{path: "/api/items/cat", value: 2},
+		pathAndValue{path: "/api/{param1}/items", value: 3},
+		pathAndValue{path: "/api/items/", value: 4},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type visited struct {
+		fullPath         string
+		pathParamCounter int
+		value            any
+	}
+	var got []visited
+	pt.Walk(func(node *TrieNode) bool {
+		got = append(got, visited{
+			fullPath:         node.FullPath,
+			pathParamCounter: node.PathParamCounter,
+			value:            node.Value,
+		})
+		return true
+	})
+	sort.Slice(got, func(i, j int) bool {
+		return got[i].fullPath < got[j].fullPath
+	})
+
+	want := []visited{
+		{fullPath: "/api/items", pathParamCounter: 0, value: 1},
+		{fullPath: "/api/items/", pathParamCounter: 0, value: 4},
+		{fullPath: "/api/items/cat", pathParamCounter: 0, value: 2},
+		{fullPath: "/api/{param1}/items", pathParamCounter: 1, value: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk() visited %v, want %v", got, want)
+	}
+}
+
+func TestPathTrie_Walk_markerPaths(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/", value: 1},
+		pathAndValue{path: "/v1/foo", value: 2},
+		pathAndValue{path: "/v1/foo/", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	var walked []string
+	pt.Walk(func(node *TrieNode) bool {
+		walked = append(walked, node.FullPath)
+		return true
+	})
+	var all []string
+	for path := range pt.All() {
+		all = append(all, path)
+	}
+	sort.Strings(walked)
+	sort.Strings(all)
+	if want := []string{"/", "/v1/foo", "/v1/foo/"}; !reflect.DeepEqual(walked, want) || !reflect.DeepEqual(all, want) {
+		t.Errorf("Walk() visited %v and All() yielded %v, want %v", walked, all, want)
+	}
+	if got := pt.Size(); got != 3 {
+		t.Errorf("Size() = %v, want 3", got)
+	}
+
+	children := pt.GetChildren()
+	sort.Strings(children)
+	if want := []string{"/", "/v1", "/v1/foo", "/v1/foo/"}; !reflect.DeepEqual(children, want) {
+		t.Errorf("GetChildren() = %v, want %v", children, want)
+	}
+	children = pt.GetChildrenToDepth(1)
+	sort.Strings(children)
+	if want := []string{"/", "/v1"}; !reflect.DeepEqual(children, want) {
+		t.Errorf("GetChildrenToDepth() = %v, want %v", children, want)
+	}
+}
+
+func TestPathTrie_Walk_emptyPath(t *testing.T) {
+	pt := New()
+	pt.Insert("", 1)
+
+	if got := pt.GetChildren(); !reflect.DeepEqual(got, []string{"/"}) {
+		t.Errorf("GetChildren() = %v, want [/]", got)
+	}
+	if got := pt.Size(); got != 1 {
+		t.Errorf("Size() = %v, want 1", got)
+	}
+}
+
+func TestPathTrie_Walk_stopsEarly(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/api/items", value: 1},
+		pathAndValue{path: "/api/items/cat", value: 2},
+		pathAndValue{path: "/api/dogs", value: 3},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	pt.Walk(func(node *TrieNode) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("Walk() called fn %d times, want 1", calls)
+	}
+}
+
+func TestPathTrie_SizeAndNodeCount(t *testing.T) {
+	tests := []struct {
+		name          string
+		pt            PathTrie
+		paths         []pathAndValue
+		wantSize      int
+		wantNodeCount int
+	}{
+		{
+			name:          "nil trie",
+			pt:            PathTrie{PathSeparator: "/"},
+			wantSize:      0,
+			wantNodeCount: 0,
+		},
+		{
+			name:          "empty trie",
+			pt:            New(),
+			wantSize:      0,
+			wantNodeCount: 0,
+		},
+		{
+			name: "intermediate nodes are counted as nodes only",
+			pt:   New(),
+			paths: []pathAndValue{
+				{path: "/api/items", value: 1},
+				{path: "/api/items/cat", value: 2},
+				{path: "/api/{param1}/items", value: 3},
+			},
+			// "", api, items, cat, {param1}, items
+			wantSize:      3,
+			wantNodeCount: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, pathNdValue := range tt.paths {
+				tt.pt.Insert(pathNdValue.path, pathNdValue.value)
+			}
+			if got := tt.pt.Size(); got != tt.wantSize {
+				t.Errorf("Size() = %v, want %v", got, tt.wantSize)
+			}
+			if got := tt.pt.NodeCount(); got != tt.wantNodeCount {
+				t.Errorf("NodeCount() = %v, want %v", got, tt.wantNodeCount)
+			}
+		})
+	}
+}
+
+func TestPathTrie_GetChildrenOf(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/billing", value: 1},
+		pathAndValue{path: "/v1/billing/invoices", value: 2},
+		pathAndValue{path: "/v1/billing/invoices/{id}", value: 3},
+		pathAndValue{path: "/v1/tenants/{tenant}/users", value: 4},
+		pathAndValue{path: "/v1/tenants/{tenant}/users/{id}", value: 5},
+		pathAndValue{path: "/v2/users", value: 6},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		prefix string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "static prefix",
+			args: args{
+				prefix: "/v1/billing",
+			},
+			want: []string{"/v1/billing/invoices", "/v1/billing/invoices/{id}"},
+		},
+		{
+			name: "prefix without value",
+			args: args{
+				prefix: "/v1",
+			},
+			want: []string{
+				"/v1/billing",
+				"/v1/billing/invoices",
+				"/v1/billing/invoices/{id}",
+				"/v1/tenants/{tenant}/users",
+				"/v1/tenants/{tenant}/users/{id}",
+			},
+		},
+		{
+			name: "concrete prefix matching a path param node",
+			args: args{
+				prefix: "/v1/tenants/acme",
+			},
+			want: []string{"/v1/tenants/{tenant}/users", "/v1/tenants/{tenant}/users/{id}"},
+		},
+		{
+			name: "leaf prefix",
+			args: args{
+				prefix: "/v2/users",
+			},
+			want: []string{},
+		},
+		{
+			name: "unknown prefix",
+			args: args{
+				prefix: "/v3",
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pt.GetChildrenOf(tt.args.prefix)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetChildrenOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_GetChildrenOf_markerPaths(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/", value: 1},
+		pathAndValue{path: "/v1/users", value: 2},
+		pathAndValue{path: "/v1/users/", value: 3},
+		pathAndValue{path: "/v1/users/{id}/", value: 4},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		prefix string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "root prefix",
+			args: args{
+				prefix: "/",
+			},
+			want: []string{"/v1/users", "/v1/users/", "/v1/users/{id}/"},
+		},
+		{
+			name: "paths ending with a separator",
+			args: args{
+				prefix: "/v1/users",
+			},
+			want: []string{"/v1/users/{id}/"},
+		},
+		{
+			name: "prefix ending with a separator",
+			args: args{
+				prefix: "/v1/users/",
+			},
+			want: []string{"/v1/users/{id}/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pt.GetChildrenOf(tt.args.prefix)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetChildrenOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_PathsWithValue(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: "users"},
+		pathAndValue{path: "/v1/users", value: "users"},
+		pathAndValue{path: "/v1/users/{id}/orders/", value: "orders"},
+		pathAndValue{path: "/v1/orders", value: "orders"},
+		pathAndValue{path: "/v2/users", value: "users"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		match func(val any) bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "group users",
+			args: args{
+				match: func(val any) bool {
+					return val == "users"
+				},
+			},
+			want: []string{"/v1/users", "/v1/users/{id}", "/v2/users"},
+		},
+		{
+			name: "group orders including trailing separator",
+			args: args{
+				match: func(val any) bool {
+					return val == "orders"
+				},
+			},
+			want: []string{"/v1/orders", "/v1/users/{id}/orders/"},
+		},
+		{
+			name: "no match",
+			args: args{
+				match: func(val any) bool {
+					return val == "invoices"
+				},
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pt.PathsWithValue(tt.args.match); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PathsWithValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTrie_Reduce(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/tenants/{tenant}", value: 1},
+		pathAndValue{path: "/v1/tenants/{tenant}/users", value: 2},
+		pathAndValue{path: "/v1/tenants/{tenant}/users/", value: 4},
+		pathAndValue{path: "/v1/tenants/{tenant}/orders/{id}", value: 8},
+		pathAndValue{path: "/v1/health", value: 16},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	sum := func(acc, val any) any {
+		return acc.(int) + val.(int)
+	}
+
+	type args struct {
+		prefix string
+	}
+	tests := []struct {
+		name string
+		args args
+		want any
+	}{
+		{
+			name: "templated prefix",
+			args: args{
+				prefix: "/v1/tenants/acme",
+			},
+			want: 15,
+		},
+		{
+			name: "prefix without value",
+			args: args{
+				prefix: "/v1",
+			},
+			want: 31,
+		},
+		{
+			name: "missing prefix",
+			args: args{
+				prefix: "/v2",
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pt.Reduce(tt.args.prefix, 0, sum); got != tt.want {
+				t.Errorf("Reduce() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Values are visited in FullPath order.
+	got := pt.Reduce("/v1/tenants/{tenant}/users", []any(nil), func(acc, val any) any {
+		return append(acc.([]any), val)
+	})
+	if want := []any{2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reduce() = %v, want %v", got, want)
+	}
+}
+
+func TestPathTrie_RootSegments(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/users/{id}", value: 1},
+		pathAndValue{path: "/orders", value: 2},
+		pathAndValue{path: "/orders/{id}/items", value: 3},
+		pathAndValue{path: "/", value: 4},
+		pathAndValue{path: "internal/metrics", value: 5},
+		pathAndValue{path: "users", value: 6},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"internal", "orders", "users"}
+	if got := pt.RootSegments(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RootSegments() = %v, want %v", got, want)
+	}
+
+	pt.Compress()
+	if got := pt.RootSegments(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RootSegments() after Compress() = %v, want %v", got, want)
+	}
+
+	empty := New()
+	if got := empty.RootSegments(); len(got) != 0 {
+		t.Errorf("RootSegments() = %v, want none", got)
+	}
+}
+
+func TestPathTrie_TemplatedAndLiteralPaths(t *testing.T) {
+	pt := New()
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/{id}", value: 1},
+		pathAndValue{path: "/v1/users", value: 2},
+		pathAndValue{path: "/v1/users/{id}/posts/", value: 3},
+		pathAndValue{path: "/v1/files/**", value: 4},
+		pathAndValue{path: "/v1/health/", value: 5},
+		pathAndValue{path: "/", value: 6},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	wantTemplated := []string{"/v1/files/**", "/v1/users/{id}", "/v1/users/{id}/posts/"}
+	if got := pt.TemplatedPaths(); !reflect.DeepEqual(got, wantTemplated) {
+		t.Errorf("TemplatedPaths() = %v, want %v", got, wantTemplated)
+	}
+	wantLiteral := []string{"/", "/v1/health/", "/v1/users"}
+	if got := pt.LiteralPaths(); !reflect.DeepEqual(got, wantLiteral) {
+		t.Errorf("LiteralPaths() = %v, want %v", got, wantLiteral)
+	}
+
+	empty := New()
+	if got := empty.TemplatedPaths(); !reflect.DeepEqual(got, []string{}) {
+		t.Errorf("TemplatedPaths() = %v, want []", got)
+	}
+}
+
+func TestTrieNode_ChildrenNames(t *testing.T) {
+	pt := New()
+	pt.CaseInsensitive = true
+	if err := populateDummyPathsAndValue(pt,
+		pathAndValue{path: "/v1/users/", value: 1},
+		pathAndValue{path: "/v1/Orders", value: 2},
+		pathAndValue{path: "/v1/{id}", value: 3},
+		pathAndValue{path: "/v1/", value: 4},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	v1 := pt.Trie[""].Child("v1")
+	if v1 == nil {
+		t.Fatal("Child(v1) = nil, want node")
+	}
+	want := []string{"Orders", "users", "{id}"}
+	if got := v1.ChildrenNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ChildrenNames() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name         string
+		wantFullPath string
+	}{
+		{name: "users", wantFullPath: "/v1/users"},
+		{name: "Orders", wantFullPath: "/v1/Orders"},
+		{name: "{id}", wantFullPath: "/v1/{id}"},
+		{name: "orders"},
+		{name: "missing"},
+		{name: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := v1.Child(tt.name)
+			switch {
+			case tt.wantFullPath == "" && got != nil:
+				t.Errorf("Child() = %v, want nil", got.FullPath)
+			case tt.wantFullPath != "" && (got == nil || got.FullPath != tt.wantFullPath):
+				t.Errorf("Child() = %v, want %v", got, tt.wantFullPath)
+			}
+		})
+	}
+
+	if got := v1.Child("users").ChildrenNames(); len(got) != 0 {
+		t.Errorf("ChildrenNames() = %v, want none", got)
+	}
+}
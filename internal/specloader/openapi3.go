@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package specloader
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+	"github.com/5gsec/api-speculator/internal/util"
+)
+
+// LoadOpenAPI3 parses an OpenAPI 3 document and returns a PathTrie holding the
+// operations of each of its paths, prefixed with the path of its first server
+// URL.
+func LoadOpenAPI3(r io.Reader) (*pathtrie.PathTrie, error) {
+	trie := pathtrie.New(pathtrie.WithDecodeSegments())
+	if err := StreamOpenAPI3(r, func(_, _ string, op any) error {
+		storeOperation(&trie, op.(Operation))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &trie, nil
+}
+
+// StreamOpenAPI3 parses an OpenAPI 3 document and calls fn with the uppercased
+// method, the path and the Operation of each of its operations, in document
+// order, without building a PathTrie. The path is prefixed and templated as in
+// LoadOpenAPI3. The whole document is read and its model built before the first
+// call, as resolving references needs it, so only the PathTrie is saved. The
+// first error returned by fn stops the iteration and is returned wrapped.
+func StreamOpenAPI3(r io.Reader, fn func(method, path string, op any) error) error {
+	model, err := buildOpenAPI3Model(r)
+	if err != nil {
+		return err
+	}
+	if model.Paths == nil {
+		return nil
+	}
+
+	basePath := serversBasePath(model.Servers)
+	for pathItems := model.Paths.PathItems.First(); pathItems != nil; pathItems = pathItems.Next() {
+		path := joinBasePath(basePath, pathItems.Key())
+		for operations := pathItems.Value().GetOperations().First(); operations != nil; operations = operations.Next() {
+			op := operations.Value()
+			method := strings.ToUpper(operations.Key())
+			if err := fn(method, path, Operation{
+				Method:      method,
+				Path:        path,
+				OperationID: op.OperationId,
+				Summary:     op.Summary,
+				Tags:        op.Tags,
+				Deprecated:  op.Deprecated != nil && *op.Deprecated,
+			}); err != nil {
+				return fmt.Errorf("failed to handle `%s %s`: %w", method, path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// buildOpenAPI3Model reads and parses an OpenAPI 3 document.
+func buildOpenAPI3Model(r io.Reader) (*v3.Document, error) {
+	specBytes, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI 3 spec: %w", err)
+	}
+
+	document, err := libopenapi.NewDocument(specBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI 3 spec: %w", err)
+	}
+	if version := document.GetVersion(); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version `%s`, expected 3.x", version)
+	}
+
+	if err := checkPathItemRefs(specBytes); err != nil {
+		return nil, fmt.Errorf("failed to resolve OpenAPI 3 spec: %w", err)
+	}
+
+	model, errs := document.BuildV3Model()
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to build OpenAPI 3 model: %w", errs[0])
+	}
+
+	return &model.Model, nil
+}
+
+// serversBasePath returns the path of the first server URL, with its variables
+// replaced by their default value.
+func serversBasePath(servers []*v3.Server) string {
+	if len(servers) == 0 {
+		return ""
+	}
+
+	serverURL := servers[0].URL
+	if servers[0].Variables != nil {
+		for variables := servers[0].Variables.First(); variables != nil; variables = variables.Next() {
+			serverURL = strings.ReplaceAll(serverURL, "{"+variables.Key()+"}", variables.Value().Default)
+		}
+	}
+
+	return util.URLPath(serverURL)
+}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package specloader
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOpenAPI3(t *testing.T) {
+	f, err := os.Open("testdata/petstore-openapi3.yaml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	trie, err := LoadOpenAPI3(f)
+	require.NoError(t, err)
+
+	children := trie.GetChildren()
+	sort.Strings(children)
+	assert.Equal(t, []string{"/v1", "/v1/pets", "/v1/pets/{petId}", "/v1/pets/{petId}/photos"}, children)
+
+	assert.Equal(t, map[string]any{
+		"GET": Operation{
+			Method:      "GET",
+			Path:        "/v1/pets",
+			OperationID: "listPets",
+			Summary:     "List all pets",
+			Tags:        []string{"pets"},
+		},
+		"POST": Operation{
+			Method:      "POST",
+			Path:        "/v1/pets",
+			OperationID: "createPet",
+			Tags:        []string{"pets"},
+		},
+	}, trie.GetValue("/v1/pets"))
+
+	operations, ok := trie.GetValue("/v1/pets/42").(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "getPet", operations["GET"].(Operation).OperationID)
+	assert.True(t, operations["DELETE"].(Operation).Deprecated)
+}
+
+func TestLoadOpenAPI3_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "not a spec",
+			input:   "not: [a spec",
+			wantErr: "failed to parse OpenAPI 3 spec",
+		},
+		{
+			name:    "swagger 2 spec",
+			input:   "swagger: \"2.0\"\ninfo:\n  title: t\n  version: 1.0.0\npaths: {}\n",
+			wantErr: "unsupported OpenAPI version `2.0`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadOpenAPI3(strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadOpenAPI3_noServers(t *testing.T) {
+	spec := `openapi: 3.0.3
+info:
+  title: t
+  version: 1.0.0
+paths:
+  /users/{id}/:
+    get:
+      responses:
+        "200":
+          description: ok
+`
+	trie, err := LoadOpenAPI3(strings.NewReader(spec))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/users", "/users/{id}"}, sortedChildren(trie.GetChildren()))
+}
+
+func sortedChildren(children []string) []string {
+	sort.Strings(children)
+	return children
+}
+
+func TestLoadOpenAPI3_refs(t *testing.T) {
+	f, err := os.Open("testdata/refs-openapi3.yaml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	trie, err := LoadOpenAPI3(f)
+	require.NoError(t, err)
+
+	for _, path := range []string{"/users/{id}", "/admins/{id}"} {
+		assert.Equal(t, map[string]any{
+			"GET": Operation{
+				Method:      "GET",
+				Path:        path,
+				OperationID: "getUser",
+			},
+			"DELETE": Operation{
+				Method:      "DELETE",
+				Path:        path,
+				OperationID: "deleteUser",
+				Deprecated:  true,
+			},
+		}, trie.GetValue(path), path)
+	}
+}
+
+func TestLoadOpenAPI3_externalRef(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: t
+  version: 1.0.0
+paths:
+  /users:
+    $ref: "./users.yaml#/components/pathItems/User"
+`
+	_, err := LoadOpenAPI3(strings.NewReader(spec))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "path `/users` references external document `./users.yaml#/components/pathItems/User`")
+}
+
+func TestStreamOpenAPI3(t *testing.T) {
+	f, err := os.Open("testdata/petstore-openapi3.yaml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	var got []string
+	err = StreamOpenAPI3(f, func(method, path string, op any) error {
+		operation, ok := op.(Operation)
+		require.True(t, ok)
+		assert.Equal(t, method, operation.Method)
+		assert.Equal(t, path, operation.Path)
+		got = append(got, method+" "+path)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"GET /v1/pets",
+		"POST /v1/pets",
+		"GET /v1/pets/{petId}",
+		"DELETE /v1/pets/{petId}",
+		"GET /v1/pets/{petId}/photos",
+	}, got)
+}
+
+func TestStreamOpenAPI3_documentOrder(t *testing.T) {
+	spec := `openapi: 3.0.0
+info:
+  title: orders
+  version: 1.0.0
+paths:
+  /orders/{orderId}:
+    delete:
+      responses:
+        "204":
+          description: deleted
+    get:
+      responses:
+        "200":
+          description: order
+  /orders:
+    post:
+      responses:
+        "201":
+          description: created
+`
+	var got []string
+	err := StreamOpenAPI3(strings.NewReader(spec), func(method, path string, _ any) error {
+		got = append(got, method+" "+path)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"DELETE /orders/{orderId}",
+		"GET /orders/{orderId}",
+		"POST /orders",
+	}, got)
+}
+
+func TestStreamOpenAPI3_abort(t *testing.T) {
+	f, err := os.Open("testdata/petstore-openapi3.yaml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = StreamOpenAPI3(f, func(string, string, any) error {
+		calls++
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+}
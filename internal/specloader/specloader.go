@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+// Package specloader loads API specs into a pathtrie.PathTrie.
+//
+// Each path of the spec is stored with its base path prefix, e.g. the OpenAPI 3
+// server URL path or the Swagger 2 basePath, and templated the same way as the
+// spec paths compared against traffic, see apispec.UnifyParameterizedPathIfApplicable.
+// The value of each path is a map[string]any of its Operation by uppercased
+// HTTP method, so that the tries built from either spec version are the same.
+// Local $ref pointers, e.g. to a components/pathItems entry, are resolved
+// before insertion, whereas references to other documents are not supported.
+package specloader
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/5gsec/api-speculator/internal/apispec"
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+// Operation holds the version-independent details of a spec operation.
+type Operation struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Tags        []string
+	Deprecated  bool
+}
+
+// insertOperation stores op in trie at the templated path prefixed with
+// basePath.
+func insertOperation(trie *pathtrie.PathTrie, basePath, path string, op Operation) {
+	op.Method = strings.ToUpper(op.Method)
+	op.Path = joinBasePath(basePath, path)
+	storeOperation(trie, op)
+}
+
+// storeOperation stores op in trie at its path, alongside the operations of the
+// other methods of the path.
+func storeOperation(trie *pathtrie.PathTrie, op Operation) {
+	trie.InsertMerge(op.Path, map[string]any{op.Method: op}, func(existing, newV *any) {
+		operations, ok := (*existing).(map[string]any)
+		if !ok {
+			*existing = *newV
+			return
+		}
+		for method, op := range (*newV).(map[string]any) {
+			operations[method] = op
+		}
+	})
+}
+
+// joinBasePath prefixes the templated path with basePath.
+func joinBasePath(basePath, path string) string {
+	basePath = strings.TrimSuffix(basePath, "/")
+	if basePath != "" && !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
+	}
+
+	return apispec.UnifyParameterizedPathIfApplicable(basePath+path, true)
+}
+
+// checkPathItemRefs returns an error if a path item of the spec references
+// another document, which can't be resolved.
+func checkPathItemRefs(specBytes []byte) error {
+	var spec struct {
+		Paths map[string]struct {
+			Ref string `yaml:"$ref"`
+		} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(specBytes, &spec); err != nil {
+		return err
+	}
+
+	for path, pathItem := range spec.Paths {
+		if pathItem.Ref != "" && !strings.HasPrefix(pathItem.Ref, "#") {
+			return fmt.Errorf("path `%s` references external document `%s`, only local $ref are supported",
+				path, pathItem.Ref)
+		}
+	}
+
+	return nil
+}
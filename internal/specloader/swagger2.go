@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package specloader
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+// LoadSwagger2 parses a Swagger 2 document and returns a PathTrie holding the
+// operations of each of its paths, prefixed with its basePath. The trie is the
+// same as the one built by LoadOpenAPI3 from the equivalent OpenAPI 3 spec.
+func LoadSwagger2(r io.Reader) (*pathtrie.PathTrie, error) {
+	specBytes, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Swagger 2 spec: %w", err)
+	}
+
+	document, err := libopenapi.NewDocument(specBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Swagger 2 spec: %w", err)
+	}
+	if version := document.GetVersion(); !strings.HasPrefix(version, "2.") {
+		return nil, fmt.Errorf("unsupported Swagger version `%s`, expected 2.x", version)
+	}
+
+	if err := checkPathItemRefs(specBytes); err != nil {
+		return nil, fmt.Errorf("failed to resolve Swagger 2 spec: %w", err)
+	}
+
+	model, errs := document.BuildV2Model()
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to build Swagger 2 model: %w", errs[0])
+	}
+
+	trie := pathtrie.New(pathtrie.WithDecodeSegments())
+	if model.Model.Paths == nil {
+		return &trie, nil
+	}
+
+	for pathItems := model.Model.Paths.PathItems.First(); pathItems != nil; pathItems = pathItems.Next() {
+		for operations := pathItems.Value().GetOperations().First(); operations != nil; operations = operations.Next() {
+			op := operations.Value()
+			insertOperation(&trie, model.Model.BasePath, pathItems.Key(), Operation{
+				Method:      operations.Key(),
+				OperationID: op.OperationId,
+				Summary:     op.Summary,
+				Tags:        op.Tags,
+				Deprecated:  op.Deprecated,
+			})
+		}
+	}
+
+	return &trie, nil
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package specloader
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSwagger2(t *testing.T) {
+	swagger, err := os.Open("testdata/petstore-swagger2.yaml")
+	require.NoError(t, err)
+	defer swagger.Close()
+	openAPI, err := os.Open("testdata/petstore-openapi3.yaml")
+	require.NoError(t, err)
+	defer openAPI.Close()
+
+	got, err := LoadSwagger2(swagger)
+	require.NoError(t, err)
+	want, err := LoadOpenAPI3(openAPI)
+	require.NoError(t, err)
+
+	assert.Equal(t, sortedChildren(want.GetChildren()), sortedChildren(got.GetChildren()))
+	assert.Equal(t, want.Trie, got.Trie)
+}
+
+func TestLoadSwagger2_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "not a spec",
+			input:   "not: [a spec",
+			wantErr: "failed to parse Swagger 2 spec",
+		},
+		{
+			name:    "OpenAPI 3 spec",
+			input:   "openapi: 3.0.3\ninfo:\n  title: t\n  version: 1.0.0\npaths: {}\n",
+			wantErr: "unsupported Swagger version `3.0.3`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSwagger2(strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package trafficloader
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+const (
+	// DefaultEnvoyPathField is the path field of Envoy JSON access logs.
+	DefaultEnvoyPathField = "path"
+
+	envoyMethodField       = "method"
+	envoyResponseCodeField = "response_code"
+)
+
+// gzipMagic are the first bytes of gzip streams.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// FromEnvoyJSON parses newline-delimited JSON access logs, such as Envoy's, and
+// returns a PathTrie holding their requests, along with the counts of parsed
+// and skipped lines. The path is read from pathField, DefaultEnvoyPathField if
+// empty, along with the method and response_code fields. Gzip-compressed input
+// is decompressed. The malformed lines are skipped.
+func FromEnvoyJSON(r io.Reader, pathField string) (*pathtrie.PathTrie, LoadStats, error) {
+	if pathField == "" {
+		pathField = DefaultEnvoyPathField
+	}
+
+	reader := bufio.NewReader(r)
+	if magic, _ := reader.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, LoadStats{}, fmt.Errorf("failed to decompress access log: %w", err)
+		}
+		defer gzipReader.Close()
+		reader = bufio.NewReader(gzipReader)
+	}
+
+	trie := pathtrie.New(pathtrie.WithDecodeSegments())
+	var stats LoadStats
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if parseEnvoyLine(&trie, pathField, line) {
+				stats.Parsed++
+			} else {
+				stats.Skipped++
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, stats, fmt.Errorf("failed to read access log: %w", err)
+		}
+	}
+
+	return &trie, stats, nil
+}
+
+// parseEnvoyLine inserts the request of the JSON line into trie and returns
+// true on success.
+func parseEnvoyLine(trie *pathtrie.PathTrie, pathField string, line []byte) bool {
+	var entry map[string]any
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return false
+	}
+
+	path, _ := entry[pathField].(string)
+	method, _ := entry[envoyMethodField].(string)
+	if method == "" {
+		return false
+	}
+	status, _ := entry[envoyResponseCodeField].(float64)
+
+	return insertRequest(trie, method, path, int(status))
+}
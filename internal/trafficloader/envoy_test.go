@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package trafficloader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnvoyJSON(t *testing.T) {
+	log, err := os.ReadFile("testdata/envoy.jsonl")
+	require.NoError(t, err)
+
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	_, err = gzipWriter.Write(log)
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{
+			name:  "plain",
+			input: log,
+		},
+		{
+			name:  "gzip",
+			input: compressed.Bytes(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trie, stats, err := FromEnvoyJSON(bytes.NewReader(tt.input), "")
+			require.NoError(t, err)
+
+			assert.Equal(t, LoadStats{Parsed: 3, Skipped: 2}, stats)
+			assert.Equal(t, map[string]any{
+				"GET": Operation{Method: "GET", Hits: 2, StatusCodes: map[int]int{200: 1, 404: 1}},
+			}, trie.GetValue("/v1/users/{param1}"))
+			assert.Equal(t, map[string]any{
+				"POST": Operation{Method: "POST", Hits: 1, StatusCodes: map[int]int{201: 1}},
+			}, trie.GetValue("/v1/orders"))
+		})
+	}
+}
+
+func TestFromEnvoyJSON_pathField(t *testing.T) {
+	log := `{"method":"GET","x_original_path":"/v1/health","path":"/healthz","response_code":200}`
+
+	trie, _, err := FromEnvoyJSON(strings.NewReader(log), "x_original_path")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/v1/health"}, trie.PathsWithValue(func(any) bool { return true }))
+}
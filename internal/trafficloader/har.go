@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package trafficloader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+type harEntry struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+	} `json:"request"`
+	Response struct {
+		Status int `json:"status"`
+	} `json:"response"`
+}
+
+// FromHAR parses an HTTP Archive and returns a PathTrie holding the requests of
+// its entries, along with the counts of parsed and skipped entries. The
+// malformed entries are skipped.
+func FromHAR(r io.Reader) (*pathtrie.PathTrie, LoadStats, error) {
+	var har struct {
+		Log struct {
+			Entries []json.RawMessage `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, LoadStats{}, fmt.Errorf("failed to parse HAR: %w", err)
+	}
+
+	trie := pathtrie.New(pathtrie.WithDecodeSegments())
+	var stats LoadStats
+	for _, rawEntry := range har.Log.Entries {
+		var entry harEntry
+		if err := json.Unmarshal(rawEntry, &entry); err != nil || entry.Request.Method == "" ||
+			!insertRequest(&trie, entry.Request.Method, entry.Request.URL, entry.Response.Status) {
+			stats.Skipped++
+			continue
+		}
+		stats.Parsed++
+	}
+
+	return &trie, stats, nil
+}
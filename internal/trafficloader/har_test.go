@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package trafficloader
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromHAR(t *testing.T) {
+	file, err := os.Open("testdata/session.har")
+	require.NoError(t, err)
+	defer file.Close()
+
+	trie, stats, err := FromHAR(file)
+	require.NoError(t, err)
+
+	assert.Equal(t, LoadStats{Parsed: 4, Skipped: 3}, stats)
+	assert.Equal(t, map[string]any{
+		"GET":    Operation{Method: "GET", Hits: 2, StatusCodes: map[int]int{200: 1, 404: 1}},
+		"DELETE": Operation{Method: "DELETE", Hits: 1, StatusCodes: map[int]int{204: 1}},
+	}, trie.GetValue("/v1/users/{param1}"))
+	assert.Equal(t, map[string]any{
+		"POST": Operation{Method: "POST", Hits: 1, StatusCodes: map[int]int{201: 1}},
+	}, trie.GetValue("/v1/orders"))
+	assert.Equal(t, 2, trie.Size())
+}
+
+func TestFromHAR_invalid(t *testing.T) {
+	_, _, err := FromHAR(strings.NewReader(`{"log": `))
+	assert.ErrorContains(t, err, "failed to parse HAR")
+}
+
+func TestFromHAR_encodedSeparator(t *testing.T) {
+	trie, stats, err := FromHAR(strings.NewReader(`{"log": {"entries": [
+		{"request": {"method": "GET", "url": "https://api.example.com/files/a%2Fb"}, "response": {"status": 200}},
+		{"request": {"method": "GET", "url": "https://api.example.com/files/a%20b"}, "response": {"status": 200}}
+	]}}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, LoadStats{Parsed: 2}, stats)
+	assert.Equal(t, 2, trie.Size())
+	assert.NotNil(t, trie.GetValue("/files/a%2Fb"))
+	assert.NotNil(t, trie.GetValue("/files/a b"))
+	assert.Nil(t, trie.GetValue("/files/a/b"))
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package trafficloader
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+const (
+	// NginxCommonFormat is the name of the common log format:
+	// $remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent
+	NginxCommonFormat = "common"
+
+	// NginxCombinedFormat is the name of the nginx default log format, which
+	// appends "$http_referer" "$http_user_agent" to the common one.
+	NginxCombinedFormat = "combined"
+)
+
+var nginxLogFormats = map[string]*regexp.Regexp{
+	NginxCommonFormat:   regexp.MustCompile(`^\S+ \S+ \S+ \[[^\]]*\] "([^"]*)" (\d{3}) (?:\d+|-)$`),
+	NginxCombinedFormat: regexp.MustCompile(`^\S+ \S+ \S+ \[[^\]]*\] "([^"]*)" (\d{3}) (?:\d+|-) "[^"]*" "[^"]*"$`),
+}
+
+// FromNginxLog parses an nginx access log in the given format, either
+// NginxCommonFormat or NginxCombinedFormat, and returns a PathTrie holding its
+// requests, along with the counts of parsed and skipped lines. The log is read
+// line by line, and the lines that don't match the format or whose request has
+// no path, e.g. truncated ones, are skipped.
+func FromNginxLog(r io.Reader, format string) (*pathtrie.PathTrie, LoadStats, error) {
+	lineFormat, ok := nginxLogFormats[format]
+	if !ok {
+		return nil, LoadStats{}, fmt.Errorf("unsupported nginx log format `%s`, expected `%s` or `%s`",
+			format, NginxCommonFormat, NginxCombinedFormat)
+	}
+
+	trie := pathtrie.New(pathtrie.WithDecodeSegments())
+	var stats LoadStats
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line = strings.TrimRight(line, "\r\n"); line != "" {
+			if parseNginxLine(&trie, lineFormat, line) {
+				stats.Parsed++
+			} else {
+				stats.Skipped++
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, stats, fmt.Errorf("failed to read nginx log: %w", err)
+		}
+	}
+
+	return &trie, stats, nil
+}
+
+// parseNginxLine inserts the request of line into trie and returns true on
+// success.
+func parseNginxLine(trie *pathtrie.PathTrie, lineFormat *regexp.Regexp, line string) bool {
+	match := lineFormat.FindStringSubmatch(line)
+	if match == nil {
+		return false
+	}
+
+	// The request line is e.g. `GET /v1/users?id=1 HTTP/1.1`.
+	request := strings.Fields(match[1])
+	if len(request) < 2 {
+		return false
+	}
+	status, err := strconv.Atoi(match[2])
+	if err != nil {
+		return false
+	}
+
+	return insertRequest(trie, request[0], request[1], status)
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package trafficloader
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromNginxLog(t *testing.T) {
+	file, err := os.Open("testdata/access.log")
+	require.NoError(t, err)
+	defer file.Close()
+
+	trie, stats, err := FromNginxLog(file, NginxCombinedFormat)
+	require.NoError(t, err)
+
+	assert.Equal(t, LoadStats{Parsed: 4, Skipped: 2}, stats)
+	assert.Equal(t, map[string]any{
+		"GET": Operation{Method: "GET", Hits: 3, StatusCodes: map[int]int{200: 2, 404: 1}},
+	}, trie.GetValue("/v1/users/{param1}"))
+	assert.Equal(t, map[string]any{
+		"POST": Operation{Method: "POST", Hits: 1, StatusCodes: map[int]int{201: 1}},
+	}, trie.GetValue("/v1/orders"))
+}
+
+func TestFromNginxLog_common(t *testing.T) {
+	log := `127.0.0.1 - - [10/Oct/2024:13:55:36 +0000] "GET /v1/health HTTP/1.1" 200 2
+127.0.0.1 - - [10/Oct/2024:13:55:37 +0000] "GET /v1/health HTTP/1.1" 200 2 "-" "curl/8.4.0"
+127.0.0.1 - - [10/Oct/2024:13:55:38 +0000] "GET /v1/health HTTP/1.1" 503 -`
+
+	trie, stats, err := FromNginxLog(strings.NewReader(log), NginxCommonFormat)
+	require.NoError(t, err)
+
+	assert.Equal(t, LoadStats{Parsed: 2, Skipped: 1}, stats)
+	assert.Equal(t, map[string]any{
+		"GET": Operation{Method: "GET", Hits: 2, StatusCodes: map[int]int{200: 1, 503: 1}},
+	}, trie.GetValue("/v1/health"))
+}
+
+func TestFromNginxLog_unsupportedFormat(t *testing.T) {
+	_, _, err := FromNginxLog(strings.NewReader(""), "json")
+	assert.ErrorContains(t, err, "unsupported nginx log format `json`")
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+// Package trafficloader loads observed API traffic into a pathtrie.PathTrie.
+//
+// Each request path is stripped of its query string and has its dynamic
+// segments replaced by params, see apispec.UnifyParameterizedPathIfApplicable.
+// It is kept percent-encoded, the trie decoding its segments without splitting
+// them on an encoded separator, see pathtrie.PathTrie.DecodeSegments.
+// The value of each path is a map[string]any of its Operation by uppercased
+// HTTP method, like the tries built by specloader, so that both can be compared.
+// Malformed records are skipped rather than aborting the whole import.
+package trafficloader
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/5gsec/api-speculator/internal/apispec"
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+	"github.com/5gsec/api-speculator/internal/util"
+)
+
+// Operation aggregates the requests observed for a method of a path.
+type Operation struct {
+	Method string
+
+	// Hits is the number of requests.
+	Hits int
+
+	// StatusCodes counts the requests by response status code.
+	StatusCodes map[int]int
+}
+
+// EndpointStats returns the counts of op, so that pathtrie.PathTrie.StatsFor
+// can read the tries built by the loaders.
+func (op Operation) EndpointStats() pathtrie.EndpointStats {
+	statusCounts := make(map[string]int, len(op.StatusCodes))
+	for status, count := range op.StatusCodes {
+		statusCounts[strconv.Itoa(status)] = count
+	}
+
+	return pathtrie.EndpointStats{
+		MethodCounts: map[string]int{op.Method: op.Hits},
+		StatusCounts: statusCounts,
+	}
+}
+
+// LoadStats counts the records parsed and skipped by a loader.
+type LoadStats struct {
+	Parsed  int
+	Skipped int
+}
+
+// insertRequest records a request with the given response status in trie at
+// the templated path of rawURL. It returns false if rawURL has no path.
+func insertRequest(trie *pathtrie.PathTrie, method, rawURL string, status int) bool {
+	path := util.URLPath(rawURL)
+	if path == "" {
+		return false
+	}
+
+	method = strings.ToUpper(method)
+	op := Operation{
+		Method:      method,
+		Hits:        1,
+		StatusCodes: map[int]int{status: 1},
+	}
+	trie.InsertMerge(apispec.UnifyParameterizedPathIfApplicable(path, false), map[string]any{method: op},
+		func(existing, newV *any) {
+			operations, ok := (*existing).(map[string]any)
+			if !ok {
+				*existing = *newV
+				return
+			}
+			for method, newOp := range (*newV).(map[string]any) {
+				operations[method] = mergeOperations(operations[method], newOp.(Operation))
+			}
+		})
+
+	return true
+}
+
+// mergeOperations adds the hits and status codes of newOp to existing, which
+// may be nil.
+func mergeOperations(existing any, newOp Operation) Operation {
+	op, ok := existing.(Operation)
+	if !ok {
+		return newOp
+	}
+
+	op.Hits += newOp.Hits
+	for status, count := range newOp.StatusCodes {
+		op.StatusCodes[status] += count
+	}
+
+	return op
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package trafficloader
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/5gsec/api-speculator/internal/pathtrie"
+)
+
+func TestStatsFor_loadedTrie(t *testing.T) {
+	file, err := os.Open("testdata/session.har")
+	require.NoError(t, err)
+	defer file.Close()
+
+	trie, _, err := FromHAR(file)
+	require.NoError(t, err)
+
+	stats, ok := trie.StatsFor("/v1/users/42")
+	require.True(t, ok)
+	assert.Equal(t, pathtrie.EndpointStats{
+		MethodCounts: map[string]int{"GET": 2, "DELETE": 1},
+		StatusCounts: map[string]int{"200": 1, "404": 1, "204": 1},
+	}, stats)
+
+	_, ok = trie.StatsFor("/v2/unknown")
+	assert.False(t, ok)
+}
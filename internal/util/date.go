@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package util
+
+import (
+	"time"
+)
+
+// DefaultDateLayouts are the time layouts recognized by IsDateSegment, i.e. ISO
+// 8601 dates in their extended and basic formats.
+var DefaultDateLayouts = []string{"2006-01-02", "20060102"}
+
+// IsDateSegment reports whether segment is a date in one of the
+// DefaultDateLayouts, e.g. 2024-01-15 or 20240115.
+func IsDateSegment(segment string) bool {
+	return IsDateSegmentWithLayouts(segment, DefaultDateLayouts)
+}
+
+// IsDateSegmentWithLayouts reports whether segment is a valid date in one of the
+// given time layouts.
+func IsDateSegmentWithLayouts(segment string, layouts []string) bool {
+	for _, layout := range layouts {
+		if _, err := time.Parse(layout, segment); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSplitDateSegments reports whether year, month and day are the segments of a
+// date split across the path, e.g. /2024/01/15.
+func IsSplitDateSegments(year, month, day string) bool {
+	if len(year) != 4 || len(month) != 2 || len(day) != 2 {
+		return false
+	}
+
+	_, err := time.Parse("2006/01/02", year+"/"+month+"/"+day)
+	return err == nil
+}
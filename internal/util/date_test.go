@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package util
+
+import (
+	"testing"
+)
+
+func TestIsDateSegment(t *testing.T) {
+	type args struct {
+		segment string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "extended ISO date",
+			args: args{
+				segment: "2024-01-15",
+			},
+			want: true,
+		},
+		{
+			name: "basic ISO date",
+			args: args{
+				segment: "20240115",
+			},
+			want: true,
+		},
+		{
+			name: "leap day",
+			args: args{
+				segment: "2024-02-29",
+			},
+			want: true,
+		},
+		{
+			name: "invalid day",
+			args: args{
+				segment: "2023-02-29",
+			},
+			want: false,
+		},
+		{
+			name: "invalid month",
+			args: args{
+				segment: "20241315",
+			},
+			want: false,
+		},
+		{
+			name: "unpadded month",
+			args: args{
+				segment: "2024-1-15",
+			},
+			want: false,
+		},
+		{
+			name: "not a date",
+			args: args{
+				segment: "2024-version",
+			},
+			want: false,
+		},
+		{
+			name: "number",
+			args: args{
+				segment: "2024",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDateSegment(tt.args.segment); got != tt.want {
+				t.Errorf("IsDateSegment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDateSegmentWithLayouts(t *testing.T) {
+	layouts := []string{"02-01-2006", "2006-01"}
+	if !IsDateSegmentWithLayouts("15-01-2024", layouts) {
+		t.Errorf("IsDateSegmentWithLayouts(15-01-2024) = false, want true")
+	}
+	if !IsDateSegmentWithLayouts("2024-01", layouts) {
+		t.Errorf("IsDateSegmentWithLayouts(2024-01) = false, want true")
+	}
+	if IsDateSegmentWithLayouts("2024-01-15", layouts) {
+		t.Errorf("IsDateSegmentWithLayouts(2024-01-15) = true, want false")
+	}
+}
+
+func TestIsSplitDateSegments(t *testing.T) {
+	type args struct {
+		year  string
+		month string
+		day   string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "valid date",
+			args: args{
+				year:  "2024",
+				month: "01",
+				day:   "15",
+			},
+			want: true,
+		},
+		{
+			name: "unpadded day",
+			args: args{
+				year:  "2024",
+				month: "01",
+				day:   "5",
+			},
+			want: false,
+		},
+		{
+			name: "invalid month",
+			args: args{
+				year:  "2024",
+				month: "13",
+				day:   "15",
+			},
+			want: false,
+		},
+		{
+			name: "not a year",
+			args: args{
+				year:  "v1",
+				month: "01",
+				day:   "15",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsSplitDateSegments(tt.args.year, tt.args.month, tt.args.day); got != tt.want {
+				t.Errorf("IsSplitDateSegments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
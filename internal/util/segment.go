@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package util
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// NormalizeSegment percent-decodes a single path segment so that e.g. "a%20b"
+// and "a b" are the same segment. Malformed escape sequences such as "%zz" are
+// kept as is instead of failing the whole segment.
+//
+// Encoded separators "%2F" and encoded percent signs "%25" are NOT decoded,
+// only uppercased: a decoded "/" could later be taken for a path separator and
+// reach a different path than the one that was checked, and "%252F" must not
+// become "%2F" either.
+//
+// A segment whose escapes don't decode to valid UTF-8, e.g. "%C3" alone, is kept
+// as is so that it can't reach the node of another truncated multi-byte char.
+func NormalizeSegment(segment string) string {
+	if !strings.Contains(segment, "%") {
+		return segment
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(segment))
+	for idx := 0; idx < len(segment); idx++ {
+		if segment[idx] != '%' || idx+2 >= len(segment) || !isHex(segment[idx+1]) || !isHex(segment[idx+2]) {
+			sb.WriteByte(segment[idx])
+			continue
+		}
+
+		switch c := unhex(segment[idx+1])<<4 | unhex(segment[idx+2]); c {
+		case '/', '%':
+			sb.WriteString(strings.ToUpper(segment[idx : idx+3]))
+		default:
+			sb.WriteByte(c)
+		}
+		idx += 2
+	}
+
+	if decoded := sb.String(); utf8.ValidString(decoded) || !utf8.ValidString(segment) {
+		return decoded
+	}
+
+	return segment
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package util
+
+import (
+	"testing"
+)
+
+func TestNormalizeSegment(t *testing.T) {
+	type args struct {
+		segment string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "no escape",
+			args: args{
+				segment: "users",
+			},
+			want: "users",
+		},
+		{
+			name: "encoded space",
+			args: args{
+				segment: "my%20file",
+			},
+			want: "my file",
+		},
+		{
+			name: "encoded separator is kept",
+			args: args{
+				segment: "a%2Fb",
+			},
+			want: "a%2Fb",
+		},
+		{
+			name: "encoded separator is uppercased",
+			args: args{
+				segment: "a%2fb",
+			},
+			want: "a%2Fb",
+		},
+		{
+			name: "lowercase hex digits",
+			args: args{
+				segment: "%7bid%7d",
+			},
+			want: "{id}",
+		},
+		{
+			name: "malformed escape is kept",
+			args: args{
+				segment: "a%zzb",
+			},
+			want: "a%zzb",
+		},
+		{
+			name: "truncated escape is kept",
+			args: args{
+				segment: "a%2",
+			},
+			want: "a%2",
+		},
+		{
+			name: "malformed and valid escapes",
+			args: args{
+				segment: "%%41%",
+			},
+			want: "%A%",
+		},
+		{
+			name: "encoded percent is kept",
+			args: args{
+				segment: "%252f",
+			},
+			want: "%252f",
+		},
+		{
+			name: "encoded multi-byte char",
+			args: args{
+				segment: "caf%C3%A9",
+			},
+			want: "caf\u00e9",
+		},
+		{
+			name: "invalid UTF-8 is kept",
+			args: args{
+				segment: "caf%C3",
+			},
+			want: "caf%C3",
+		},
+		{
+			name: "raw multi-byte char",
+			args: args{
+				segment: "caf\u00e9%20",
+			},
+			want: "caf\u00e9 ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeSegment(tt.args.segment); got != tt.want {
+				t.Errorf("NormalizeSegment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package util
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultTokenMinLength is the minimum length of opaque tokens used by
+	// IsOpaqueToken.
+	DefaultTokenMinLength = 20
+
+	// DefaultTokenMinEntropy is the minimum Shannon entropy, in bits per char, of
+	// opaque tokens used by IsOpaqueToken.
+	DefaultTokenMinEntropy = 3.0
+)
+
+// IsOpaqueToken reports whether segment looks like a hash or an opaque token,
+// i.e. a long high-entropy hex or base64url string, using the default
+// thresholds. See IsOpaqueTokenWithThresholds.
+func IsOpaqueToken(segment string) bool {
+	return IsOpaqueTokenWithThresholds(segment, DefaultTokenMinLength, DefaultTokenMinEntropy)
+}
+
+// IsOpaqueTokenWithThresholds reports whether segment is a hex or base64url
+// string of at least minLength chars and minEntropy bits of Shannon entropy per
+// char. To never flag ordinary words, hex strings must mix letters and digits,
+// and base64url strings must mix lowercase, uppercase and at least two digits.
+func IsOpaqueTokenWithThresholds(segment string, minLength int, minEntropy float64) bool {
+	// Base64url may be padded.
+	token := strings.TrimRight(segment, "=")
+	if utf8.RuneCountInString(token) < minLength {
+		return false
+	}
+
+	digits, hexLetters, lowers, uppers := 0, 0, 0, 0
+	isHex := true
+	for _, c := range token {
+		switch {
+		case '0' <= c && c <= '9':
+			digits++
+		case 'a' <= c && c <= 'f', 'A' <= c && c <= 'F':
+			hexLetters++
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', c == '-', c == '_':
+			isHex = false
+		default:
+			return false
+		}
+		switch {
+		case 'a' <= c && c <= 'z':
+			lowers++
+		case 'A' <= c && c <= 'Z':
+			uppers++
+		}
+	}
+
+	if isHex && len(token) == len(segment) {
+		if digits == 0 || hexLetters == 0 {
+			return false
+		}
+	} else if digits < 2 || lowers == 0 || uppers == 0 {
+		return false
+	}
+
+	return shannonEntropy(token) >= minEntropy
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per rune.
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, c := range s {
+		counts[c]++
+	}
+
+	entropy := 0.0
+	length := float64(utf8.RuneCountInString(s))
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
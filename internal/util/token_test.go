@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package util
+
+import (
+	"testing"
+)
+
+func TestIsOpaqueToken(t *testing.T) {
+	type args struct {
+		segment string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "32 chars hex (md5)",
+			args: args{
+				segment: "9f86d081884c7d659a2feaa0c55ad015",
+			},
+			want: true,
+		},
+		{
+			name: "40 chars hex (sha1)",
+			args: args{
+				segment: "a94a8fe5ccb19ba61c4c0873d391e987982fbbd3",
+			},
+			want: true,
+		},
+		{
+			name: "64 chars hex (sha256)",
+			args: args{
+				segment: "9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08",
+			},
+			want: true,
+		},
+		{
+			name: "base64url",
+			args: args{
+				segment: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9",
+			},
+			want: true,
+		},
+		{
+			name: "padded base64url",
+			args: args{
+				segment: "dGhpcyBpcyBhIHRva2Vu_-Zx9Q==",
+			},
+			want: true,
+		},
+		{
+			name: "ordinary long word",
+			args: args{
+				segment: "authentication",
+			},
+			want: false,
+		},
+		{
+			name: "ordinary longer word",
+			args: args{
+				segment: "internationalization",
+			},
+			want: false,
+		},
+		{
+			name: "word with a digit",
+			args: args{
+				segment: "Version2Configuration",
+			},
+			want: false,
+		},
+		{
+			name: "low entropy",
+			args: args{
+				segment: "000000000000000000000000000000a1",
+			},
+			want: false,
+		},
+		{
+			name: "too short hex",
+			args: args{
+				segment: "9f86d081",
+			},
+			want: false,
+		},
+		{
+			name: "not base64url",
+			args: args{
+				segment: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.e30",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsOpaqueToken(tt.args.segment); got != tt.want {
+				t.Errorf("IsOpaqueToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsOpaqueTokenWithThresholds(t *testing.T) {
+	segment := "9f86d081884c7d659a2feaa0c55ad015"
+	if IsOpaqueTokenWithThresholds(segment, 40, DefaultTokenMinEntropy) {
+		t.Errorf("IsOpaqueTokenWithThresholds() = true with a higher min length, want false")
+	}
+	if IsOpaqueTokenWithThresholds(segment, DefaultTokenMinLength, 3.9) {
+		t.Errorf("IsOpaqueTokenWithThresholds() = true with a higher min entropy, want false")
+	}
+	if !IsOpaqueTokenWithThresholds("9f86d081", 8, 2.5) {
+		t.Errorf("IsOpaqueTokenWithThresholds() = false with lower thresholds, want true")
+	}
+}
+
+func Test_shannonEntropy(t *testing.T) {
+	type args struct {
+		s string
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "single char",
+			args: args{
+				s: "aaaa",
+			},
+			want: 0,
+		},
+		{
+			name: "two chars",
+			args: args{
+				s: "abab",
+			},
+			want: 1,
+		},
+		{
+			name: "multi-byte chars",
+			args: args{
+				s: "éaéa",
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shannonEntropy(tt.args.s); got != tt.want {
+				t.Errorf("shannonEntropy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
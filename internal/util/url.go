@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package util
+
+import (
+	"net/url"
+)
+
+// URLPath returns the path of rawURL, which may be relative, as escaped in
+// rawURL, e.g. /files/a%2Fb rather than /files/a/b, so that an encoded
+// separator can't become a real one. Decoding is left to the PathTrie, see
+// pathtrie.PathTrie.DecodeSegments. An empty path is returned if rawURL can't be
+// parsed.
+func URLPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	// RawPath is only set when rawURL doesn't use the default encoding of Path,
+	// returned by EscapedPath, which e.g. escapes {} as %7B%7D.
+	if u.RawPath != "" {
+		return u.RawPath
+	}
+	return u.EscapedPath()
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package util
+
+import (
+	"testing"
+)
+
+func TestURLPath(t *testing.T) {
+	type args struct {
+		rawURL string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "absolute url",
+			args: args{
+				rawURL: "https://api.example.com/v1/users?page=2",
+			},
+			want: "/v1/users",
+		},
+		{
+			name: "relative url",
+			args: args{
+				rawURL: "/v1/users#top",
+			},
+			want: "/v1/users",
+		},
+		{
+			name: "encoded separator",
+			args: args{
+				rawURL: "/files/a%2Fb",
+			},
+			want: "/files/a%2Fb",
+		},
+		{
+			name: "encoded space",
+			args: args{
+				rawURL: "/files/a%20b",
+			},
+			want: "/files/a%20b",
+		},
+		{
+			name: "path params",
+			args: args{
+				rawURL: "https://api.example.com/v1/{tenant}",
+			},
+			want: "/v1/{tenant}",
+		},
+		{
+			name: "invalid url",
+			args: args{
+				rawURL: "http://[::1/v1",
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := URLPath(tt.args.rawURL); got != tt.want {
+				t.Errorf("URLPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
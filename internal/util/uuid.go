@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package util
+
+import (
+	"github.com/gofrs/uuid"
+)
+
+const (
+	uuidLen           = 36
+	hyphenlessUUIDLen = 32
+)
+
+// IsUUID reports whether segment is a UUID in its canonical form, e.g.
+// 550e8400-e29b-41d4-a716-446655440000, or without hyphens, in any case. The
+// braced and URN forms are not considered as UUIDs.
+func IsUUID(segment string) bool {
+	if len(segment) != uuidLen && len(segment) != hyphenlessUUIDLen {
+		return false
+	}
+
+	_, err := uuid.FromString(segment)
+	return err == nil
+}
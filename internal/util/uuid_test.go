@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2024 Authors of API-Speculator
+
+package util
+
+import (
+	"testing"
+)
+
+func TestIsUUID(t *testing.T) {
+	type args struct {
+		segment string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "canonical v4",
+			args: args{
+				segment: "550e8400-e29b-41d4-a716-446655440000",
+			},
+			want: true,
+		},
+		{
+			name: "uppercase v4",
+			args: args{
+				segment: "F47AC10B-58CC-4372-A567-0E02B2C3D479",
+			},
+			want: true,
+		},
+		{
+			name: "hyphenless v4",
+			args: args{
+				segment: "f47ac10b58cc4372a5670e02b2c3d479",
+			},
+			want: true,
+		},
+		{
+			name: "nil uuid",
+			args: args{
+				segment: "00000000-0000-0000-0000-000000000000",
+			},
+			want: true,
+		},
+		{
+			name: "non hex char",
+			args: args{
+				segment: "550e8400-e29b-41d4-a716-44665544000g",
+			},
+			want: false,
+		},
+		{
+			name: "misplaced hyphen",
+			args: args{
+				segment: "550e840-0e29b-41d4-a716-446655440000",
+			},
+			want: false,
+		},
+		{
+			name: "too short",
+			args: args{
+				segment: "550e8400-e29b-41d4-a716-44665544000",
+			},
+			want: false,
+		},
+		{
+			name: "braced",
+			args: args{
+				segment: "{550e8400-e29b-41d4-a716-446655440000}",
+			},
+			want: false,
+		},
+		{
+			name: "urn",
+			args: args{
+				segment: "urn:uuid:550e8400-e29b-41d4-a716-446655440000",
+			},
+			want: false,
+		},
+		{
+			name: "word",
+			args: args{
+				segment: "users",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUUID(tt.args.segment); got != tt.want {
+				t.Errorf("IsUUID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}